@@ -0,0 +1,47 @@
+package StreamDeck
+
+import (
+	"fmt"
+)
+
+// RegisterAction registers a named action. Buttons can be bound to the
+// action by its name with BindKey. Registering an action with a name which
+// is already in use replaces the existing action.
+func (sd *StreamDeck) RegisterAction(name string, fn func()) error {
+	if name == "" {
+		return fmt.Errorf("action name must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("action %s must not be nil", name)
+	}
+
+	sd.Lock()
+	defer sd.Unlock()
+	sd.actions[name] = fn
+	return nil
+}
+
+// BindKey binds a button to a registered action. The action gets executed
+// whenever the button is pressed. An error is returned if no action with
+// the given name has been registered.
+func (sd *StreamDeck) BindKey(btnIndex int, actionName string) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+
+	sd.Lock()
+	defer sd.Unlock()
+
+	if _, ok := sd.actions[actionName]; !ok {
+		return fmt.Errorf("unknown action %s", actionName)
+	}
+	sd.bindings[btnIndex] = actionName
+	return nil
+}
+
+// UnbindKey removes the action binding of a button.
+func (sd *StreamDeck) UnbindKey(btnIndex int) {
+	sd.Lock()
+	defer sd.Unlock()
+	delete(sd.bindings, btnIndex)
+}
@@ -0,0 +1,117 @@
+package actionbutton
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	sd "github.com/AKovalevich/streamdeck"
+	"github.com/disintegration/gift"
+)
+
+// ActionButton is a Button showing an icon which executes an action when
+// it is pressed. If the action fails, the button is tinted red until the
+// action succeeds again.
+type ActionButton struct {
+	sync.Mutex
+	streamDeck *sd.StreamDeck
+	id         int
+	icon       image.Image
+	bgColor    color.Color
+	errColor   color.Color
+	action     func() error
+	failed     bool
+}
+
+// NewActionButton is the constructor for a new ActionButton. Functional
+// arguments can be supplied to modify it's default characteristics
+func NewActionButton(sd *sd.StreamDeck, id int, options ...func(*ActionButton)) (*ActionButton, error) {
+
+	if sd == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+
+	btn := &ActionButton{
+		streamDeck: sd,
+		id:         id,
+		bgColor:    color.Black,
+		errColor:   color.RGBA{200, 0, 0, 160},
+	}
+
+	for _, option := range options {
+		option(btn)
+	}
+
+	return btn, nil
+}
+
+// Change executes the action when the button is pressed and renders the
+// result.
+func (btn *ActionButton) Change(state sd.BtnState) {
+	if state != sd.BtnPressed {
+		return
+	}
+
+	btn.Lock()
+	action := btn.action
+	btn.Unlock()
+
+	if action == nil {
+		return
+	}
+
+	err := action()
+	if err != nil {
+		btn.streamDeck.Log().Warnf("action of button %d failed: %v", btn.id, err)
+	}
+
+	btn.Lock()
+	btn.failed = err != nil
+	btn.Unlock()
+
+	if err := btn.Draw(); err != nil {
+		btn.streamDeck.Log().Warn(err.Error())
+	}
+}
+
+// Failed returns true if the last execution of the action failed.
+func (btn *ActionButton) Failed() bool {
+	btn.Lock()
+	defer btn.Unlock()
+	return btn.failed
+}
+
+// Draw renders the Button
+func (btn *ActionButton) Draw() error {
+	btn.Lock()
+	defer btn.Unlock()
+
+	size := btn.streamDeck.Capabilities().KeySize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(btn.bgColor), image.Point{}, draw.Src)
+
+	if btn.icon != nil {
+		draw.Draw(img, img.Bounds(), fit(btn.icon, size), image.Point{}, draw.Over)
+	}
+
+	if btn.failed {
+		draw.Draw(img, img.Bounds(), image.NewUniform(btn.errColor), image.Point{}, draw.Over)
+	}
+
+	return btn.streamDeck.FillImage(btn.id, img)
+}
+
+// fit returns the icon if it already has the given key size, otherwise a
+// copy scaled to the key size.
+func fit(icon image.Image, size int) image.Image {
+	rect := icon.Bounds()
+	if rect.Dx() == size && rect.Dy() == size && rect.Min == (image.Point{}) {
+		return icon
+	}
+	g := gift.New(gift.Resize(size, size, gift.LanczosResampling))
+	img := image.NewRGBA(g.Bounds(rect))
+	g.Draw(img, icon)
+	return img
+}
@@ -0,0 +1,37 @@
+package actionbutton
+
+import (
+	"image"
+	"image/color"
+)
+
+// Icon is a functional option to set the icon of the ActionButton.
+func Icon(img image.Image) func(*ActionButton) {
+	return func(btn *ActionButton) {
+		btn.icon = img
+	}
+}
+
+// BgColor is a functional option which sets the background color of the
+// ActionButton.
+func BgColor(c color.Color) func(*ActionButton) {
+	return func(btn *ActionButton) {
+		btn.bgColor = c
+	}
+}
+
+// ErrorColor is a functional option which sets the color used to tint the
+// ActionButton when the action failed.
+func ErrorColor(c color.Color) func(*ActionButton) {
+	return func(btn *ActionButton) {
+		btn.errColor = c
+	}
+}
+
+// Action is a functional option to set the action executed when the
+// ActionButton is pressed.
+func Action(action func() error) func(*ActionButton) {
+	return func(btn *ActionButton) {
+		btn.action = action
+	}
+}
@@ -0,0 +1,198 @@
+package StreamDeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+)
+
+// errAnimationStopped is returned when a frame of an animation is written
+// after the animation has been stopped.
+var errAnimationStopped = errors.New("animation stopped")
+
+// animation represents an animation (e.g. a slideshow) running on a button.
+// Every button runs at most one animation at a time. Writing any other
+// content to the button stops the animation.
+type animation struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+// cancel stops the animation. It is safe to call cancel several times.
+func (a *animation) cancel() {
+	a.once.Do(func() {
+		close(a.stop)
+	})
+}
+
+// startAnimation registers a new animation for a button. A previously
+// running animation on the button is stopped.
+func (sd *StreamDeck) startAnimation(btnIndex int) *animation {
+	a := &animation{stop: make(chan struct{})}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	sd.stopAnimation(btnIndex)
+	if sd.animations == nil {
+		sd.animations = make(map[int]*animation)
+	}
+	sd.animations[btnIndex] = a
+	return a
+}
+
+// stopAnimation stops the animation running on a button (if any). The write
+// lock must be held by the caller.
+func (sd *StreamDeck) stopAnimation(btnIndex int) {
+	if a, ok := sd.animations[btnIndex]; ok {
+		a.cancel()
+		delete(sd.animations, btnIndex)
+	}
+}
+
+// stopAllAnimations stops the animations on all buttons. The write lock must
+// be held by the caller.
+func (sd *StreamDeck) stopAllAnimations() {
+	for btnIndex := range sd.animations {
+		sd.stopAnimation(btnIndex)
+	}
+}
+
+// writeAnimationFrame writes a frame of an animation to a button. Frames
+// equal to the content of the button are skipped. If the animation has been
+// stopped in the meantime, errAnimationStopped is returned and the button is
+// left untouched.
+func (sd *StreamDeck) writeAnimationFrame(btnIndex int, a *animation, img image.Image) error {
+	sd.Lock()
+	scaleMode := sd.scaleMode
+	sd.Unlock()
+
+	rect := img.Bounds()
+	if rect.Dx() != sd.model.keySize || rect.Dy() != sd.model.keySize {
+		img = sd.scale(img, sd.model.keySize, sd.model.keySize, scaleMode)
+	}
+	rgba, imgBuf := sd.encodeBtnImage(btnIndex, img)
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	if sd.animations[btnIndex] != a {
+		return errAnimationStopped
+	}
+	if sd.isCached(btnIndex, imgBuf) {
+		return nil
+	}
+	return sd.writeBtnBuf(btnIndex, rgba, imgBuf)
+}
+
+// Slideshow cycles through a list of images on a button, showing each image
+// for the given interval. The slideshow runs until the returned stop function
+// is called, other content is written to the button or the StreamDeck is
+// closed. An empty list of images does nothing; a single image is shown
+// without cycling.
+func (sd *StreamDeck) Slideshow(btnIndex int, imgs []image.Image, interval time.Duration) (stop func()) {
+	if len(imgs) == 0 || sd.checkValidKeyIndex(btnIndex) != nil {
+		return func() {}
+	}
+
+	a := sd.startAnimation(btnIndex)
+	stop = func() {
+		sd.writeMu.Lock()
+		defer sd.writeMu.Unlock()
+		if sd.animations[btnIndex] == a {
+			sd.stopAnimation(btnIndex)
+		}
+	}
+
+	if err := sd.writeAnimationFrame(btnIndex, a, imgs[0]); err != nil {
+		sd.log.Warn(err.Error())
+	}
+
+	if len(imgs) == 1 || interval <= 0 {
+		return stop
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		pos := 0
+		for {
+			select {
+			case <-ticker.C:
+				pos = (pos + 1) % len(imgs)
+				err := sd.writeAnimationFrame(btnIndex, a, imgs[pos])
+				if err == errAnimationStopped {
+					return
+				}
+				if err != nil {
+					sd.log.Warn(err.Error())
+				}
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// Animate runs an animation computed frame by frame, e.g. for clock hands,
+// spinners or pulsing effects. render is called fps times per second with
+// the time elapsed since the start and draws the frame onto dst, which is
+// transparent at the start of each frame and has the size of a button
+// multiplied with the supersampling factor. Frames which don't change the
+// content of the button are not sent. Animate blocks until ctx is cancelled
+// and returns its error; it returns nil if other content is written to the
+// button or the StreamDeck is closed, and the error of a failed write.
+func (sd *StreamDeck) Animate(ctx context.Context, btnIndex int, fps int, render func(t time.Duration, dst *image.RGBA)) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	if fps <= 0 {
+		return fmt.Errorf("invalid frame rate %d; must be positive", fps)
+	}
+	if render == nil {
+		return fmt.Errorf("render function must not be nil")
+	}
+
+	a := sd.startAnimation(btnIndex)
+	defer func() {
+		sd.writeMu.Lock()
+		defer sd.writeMu.Unlock()
+		if sd.animations[btnIndex] == a {
+			sd.stopAnimation(btnIndex)
+		}
+	}()
+
+	factor := sd.supersamplingFactor()
+	dst := image.NewRGBA(image.Rect(0, 0, sd.model.keySize*factor, sd.model.keySize*factor))
+
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		for i := range dst.Pix {
+			dst.Pix[i] = 0
+		}
+		render(time.Since(start), dst)
+
+		err := sd.writeAnimationFrame(btnIndex, a, downscale(dst, factor))
+		if err == errAnimationStopped {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// dimensions (in pixel) of the badge drawn by SetBadge
+const (
+	badgeRadius   = 11
+	badgeMargin   = 2
+	badgeFontSize = 14
+)
+
+// badgeColor is the fill color of the badge drawn by SetBadge.
+var badgeColor = color.RGBA{220, 30, 30, 255}
+
+// SetBadge overlays a small badge with a number in the top right corner of
+// the image currently shown on a button, like the notification badges of
+// app icons. Counts above 99 are shown as "99+". A count of zero removes
+// the badge and restores the image underneath. If the content of the button
+// is unknown, the badge is drawn on black. Writing any other content to the
+// button removes the badge as well.
+func (sd *StreamDeck) SetBadge(btnIndex int, count int) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("badge count must not be negative")
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	base, ok := sd.badgeBase[btnIndex]
+	if count == 0 && !ok {
+		// no badge shown
+		return nil
+	}
+	if !ok {
+		base = sd.cachedImage(btnIndex)
+		if base == nil {
+			base = image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
+			draw.Draw(base, base.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+		}
+	}
+
+	img := base
+	if count > 0 {
+		var err error
+		img, err = drawBadge(base, count)
+		if err != nil {
+			return err
+		}
+	}
+
+	rgba, imgBuf := sd.encodeBtnImage(btnIndex, img)
+	sd.stopAnimation(btnIndex)
+	if err := sd.writeBtnBuf(btnIndex, rgba, imgBuf); err != nil {
+		return err
+	}
+
+	// writeBtnBuf removes the badge; remember the image underneath
+	if count > 0 {
+		if sd.badgeBase == nil {
+			sd.badgeBase = make(map[int]*image.RGBA)
+		}
+		sd.badgeBase[btnIndex] = base
+	}
+	return nil
+}
+
+// drawBadge returns a copy of img with a badge showing count.
+func drawBadge(img *image.RGBA, count int) (*image.RGBA, error) {
+	f, err := loadDefaultFont()
+	if err != nil {
+		return nil, err
+	}
+
+	text := strconv.Itoa(count)
+	if count > 99 {
+		text = "99+"
+	}
+
+	face := truetype.NewFace(f, &truetype.Options{
+		Size:    badgeFontSize,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	textWidth := font.MeasureString(face, text).Ceil()
+
+	// the badge is a circle which is stretched into a pill for longer
+	// numbers
+	stretch := textWidth - badgeRadius
+	if stretch < 0 {
+		stretch = 0
+	}
+	rect := img.Bounds()
+	cy := float64(rect.Min.Y + badgeMargin + badgeRadius)
+	right := float64(rect.Max.X - badgeMargin - badgeRadius)
+	left := right - float64(stretch)
+
+	res := copyRGBA(img)
+	draw.DrawMask(res, res.Bounds(), image.NewUniform(badgeColor), image.Point{0, 0},
+		pillMask(res.Bounds(), left, right, cy, badgeRadius), image.Point{0, 0}, draw.Over)
+
+	metrics := face.Metrics()
+	d := &font.Drawer{
+		Dst:  res,
+		Src:  image.White,
+		Face: face,
+	}
+	x := int((left+right)/2) - rect.Min.X - textWidth/2
+	y := int(cy) - rect.Min.Y + (metrics.Ascent.Ceil()-metrics.Descent.Ceil())/2
+	d.Dot = fixed.P(x, y)
+	d.DrawString(text)
+
+	return res, nil
+}
+
+// pillMask returns an anti-aliased mask of a horizontal pill shape with the
+// given radius around the line from (left, cy) to (right, cy).
+func pillMask(bounds image.Rectangle, left, right, cy, radius float64) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			cx := math.Max(left, math.Min(right, px))
+			dist := math.Hypot(px-cx, py-cy)
+			coverage := math.Max(0, math.Min(1, radius+0.5-dist))
+			mask.SetAlpha(x, y, color.Alpha{uint8(coverage * 255)})
+		}
+	}
+	return mask
+}
@@ -0,0 +1,106 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"time"
+)
+
+// BenchResult contains the latencies measured by BenchmarkWrites. A
+// latency is the time needed to send the complete image of one button.
+type BenchResult struct {
+	// N is the amount of measured writes.
+	N     int
+	Min   time.Duration
+	Avg   time.Duration
+	Max   time.Duration
+	P99   time.Duration
+	Total time.Duration
+}
+
+func (r BenchResult) String() string {
+	return fmt.Sprintf("%d writes in %v: min %v, avg %v, max %v, p99 %v",
+		r.N, r.Total, r.Min, r.Avg, r.Max, r.P99)
+}
+
+// BenchmarkWrites measures the latency of n button writes, which helps to
+// diagnose slow USB connections and to choose frame rates. The buttons are
+// written one after another with alternating test patterns, bypassing the
+// button cache, so that every write is transmitted. Other writes are
+// blocked during the benchmark. Afterwards the previous content of the
+// buttons is restored.
+func (sd *StreamDeck) BenchmarkWrites(n int) (BenchResult, error) {
+	if n <= 0 {
+		return BenchResult{}, fmt.Errorf("amount of writes must be positive")
+	}
+
+	// two patterns, so that consecutive writes of a button differ
+	var patterns [2][]byte
+	for i, c := range []color.Color{color.White, color.RGBA{0, 0, 255, 255}} {
+		img := image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
+		draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{0, 0}, draw.Src)
+		_, patterns[i] = sd.encodeBtnImage(0, img)
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	numBtns := len(sd.cache)
+	latencies := make([]time.Duration, 0, n)
+	var benchErr error
+	for i := 0; i < n; i++ {
+		btnIndex := i % numBtns
+		start := time.Now()
+		if err := sd.sendBtnBuf(btnIndex, patterns[(i/numBtns)%2]); err != nil {
+			benchErr = err
+			break
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+
+	// restore the buttons which have been written
+	written := len(latencies) + 1
+	if written > numBtns {
+		written = numBtns
+	}
+	for btnIndex := 0; btnIndex < written; btnIndex++ {
+		buf := sd.cache[btnIndex].buf
+		if buf == nil {
+			buf = sd.blankBtnBuf()
+		}
+		if err := sd.sendBtnBuf(btnIndex, buf); err != nil && benchErr == nil {
+			benchErr = err
+		}
+	}
+
+	if benchErr != nil {
+		return BenchResult{}, benchErr
+	}
+	return newBenchResult(latencies), nil
+}
+
+// newBenchResult computes the statistics of the measured latencies.
+func newBenchResult(latencies []time.Duration) BenchResult {
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+
+	// nearest rank method
+	p99 := (len(latencies)*99 + 99) / 100
+	return BenchResult{
+		N:     len(latencies),
+		Min:   latencies[0],
+		Avg:   total / time.Duration(len(latencies)),
+		Max:   latencies[len(latencies)-1],
+		P99:   latencies[p99-1],
+		Total: total,
+	}
+}
@@ -0,0 +1,175 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// KeyState describes the visual content of a key declaratively. It is
+// rendered by a StateBinding. Two KeyStates with the same text, the same
+// colors and the identical Icon are considered to look the same, so Icon
+// should be replaced with a new image rather than modified in place.
+type KeyState struct {
+	// Background is the color filling the key. If nil, the key is black.
+	Background color.Color
+	// Icon is drawn on top of the background and scaled to fit the key.
+	Icon image.Image
+	// Text is drawn centered on top of the icon with the embedded default
+	// font (see FillText). Lines are separated by "\n".
+	Text string
+	// TextColor is the color of the text. If nil, black or white is chosen,
+	// whichever contrasts best with the background.
+	TextColor color.Color
+}
+
+// StateBinding binds the content of a key to a render function which
+// derives a KeyState from the application state. The key is only redrawn
+// if the returned KeyState differs from the one currently shown.
+type StateBinding struct {
+	sync.Mutex
+	sd       *StreamDeck
+	btnIndex int
+	render   func(prev KeyState) KeyState
+	state    KeyState
+	drawn    bool
+}
+
+// BindKeyToState binds a key to a render function. The render function
+// receives the KeyState currently shown and returns the KeyState to be
+// shown. It is evaluated immediately, whenever Update is called and, after
+// Run has been called, periodically. The key is only redrawn if the state
+// changed.
+func (sd *StreamDeck) BindKeyToState(btnIndex int, render func(prev KeyState) KeyState) (*StateBinding, error) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return nil, err
+	}
+
+	b := &StateBinding{
+		sd:       sd,
+		btnIndex: btnIndex,
+		render:   render,
+	}
+
+	return b, b.Update()
+}
+
+// Update evaluates the render function and redraws the key if the KeyState
+// has changed.
+func (b *StateBinding) Update() error {
+	b.Lock()
+	defer b.Unlock()
+
+	state := b.render(b.state)
+	if b.drawn && state.equal(b.state) {
+		return nil
+	}
+
+	if err := b.sd.drawKeyState(b.btnIndex, state); err != nil {
+		return err
+	}
+	b.state = state
+	b.drawn = true
+	return nil
+}
+
+// equal reports whether two KeyStates look the same. The fields are compared
+// individually, since == panics for colors or images of non-comparable
+// types. Icons are compared by identity.
+func (s KeyState) equal(o KeyState) bool {
+	return s.Text == o.Text &&
+		sameColor(s.Background, o.Background) &&
+		sameColor(s.TextColor, o.TextColor) &&
+		sameImage(s.Icon, o.Icon)
+}
+
+// sameColor reports whether two (possibly nil) colors have the same RGBA
+// values.
+func sameColor(a, b color.Color) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+// sameImage reports whether a and b are the identical image. Images of
+// non-comparable types (which can't be identified) are never the same.
+func sameImage(a, b image.Image) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// Invalidate forces the key to be redrawn on the next Update, e.g. after
+// other content has been written to it.
+func (b *StateBinding) Invalidate() {
+	b.Lock()
+	defer b.Unlock()
+	b.drawn = false
+}
+
+// Run evaluates the binding periodically with the given interval until the
+// returned stop function is called.
+func (b *StateBinding) Run(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := b.Update(); err != nil {
+					b.sd.log.Warn(err.Error())
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+// drawKeyState renders a KeyState onto a key.
+func (sd *StreamDeck) drawKeyState(btnIndex int, state KeyState) error {
+	return sd.DrawKey(btnIndex, func(dst *image.RGBA) {
+		bg := state.Background
+		if bg == nil {
+			bg = color.Black
+		}
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{0, 0}, draw.Src)
+
+		if state.Icon != nil {
+			size := dst.Bounds().Dx()
+			icon := sd.scale(state.Icon, size, size, ScaleFit)
+			draw.Draw(dst, dst.Bounds(), icon, icon.Bounds().Min, draw.Over)
+		}
+
+		if state.Text != "" {
+			textColor := state.TextColor
+			if textColor == nil {
+				textColor = ContrastColor(bg)
+			}
+			factor := dst.Bounds().Dx() / sd.model.keySize
+			if err := drawCenteredText(dst, state.Text, image.NewUniform(textColor), factor); err != nil {
+				sd.log.Warn(err.Error())
+			}
+		}
+	})
+}
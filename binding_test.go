@@ -0,0 +1,59 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// tiledImage is an image.Image of a non-comparable type.
+type tiledImage struct {
+	*image.RGBA
+	tiles []image.Rectangle
+}
+
+func TestStateBindingNonComparableState(t *testing.T) {
+	sd, _ := newTestDeck(t, ProductID)
+
+	icon := tiledImage{RGBA: image.NewRGBA(image.Rect(0, 0, ButtonSize, ButtonSize))}
+	renders := 0
+	b, err := sd.BindKeyToState(0, func(prev KeyState) KeyState {
+		renders++
+		return KeyState{Background: color.NRGBA{0, 0, 255, 255}, Icon: icon, Text: "A"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if renders != 2 {
+		t.Errorf("render function called %d times, want 2", renders)
+	}
+}
+
+func TestKeyStateEqual(t *testing.T) {
+	icon := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	other := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	red := KeyState{Background: color.RGBA{255, 0, 0, 255}, Icon: icon, Text: "A"}
+
+	tests := []struct {
+		name string
+		a, b KeyState
+		want bool
+	}{
+		{"zero", KeyState{}, KeyState{}, true},
+		{"identical", red, red, true},
+		{"same color of another type", red, KeyState{Background: color.NRGBA{255, 0, 0, 255}, Icon: icon, Text: "A"}, true},
+		{"other text", red, KeyState{Background: red.Background, Icon: icon, Text: "B"}, false},
+		{"other icon", red, KeyState{Background: red.Background, Icon: other, Text: "A"}, false},
+		{"nil color", red, KeyState{Icon: icon, Text: "A"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.equal(tt.b); got != tt.want {
+				t.Errorf("equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
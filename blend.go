@@ -0,0 +1,37 @@
+package StreamDeck
+
+import (
+	"image"
+)
+
+// BlendImages returns the interpolation between the images a and b. With
+// t = 0 the result equals a, with t = 1 it equals b; values outside of this
+// range are clamped. All channels including alpha are interpolated, so
+// transparent images can be blended as well. If b doesn't have the size of
+// a, it is resized. The result has the size of a with its origin at (0,0),
+// so it can be passed to FillImage or drawn in DrawKey. BlendImages is the
+// building block for custom transitions.
+func BlendImages(a, b image.Image, t float64) *image.RGBA {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+
+	ra := a.Bounds()
+	if rb := b.Bounds(); rb.Dx() != ra.Dx() || rb.Dy() != ra.Dy() {
+		b = resize(b, ra.Dx(), ra.Dy())
+	}
+
+	res := copyRGBA(a)
+	src := copyRGBA(b)
+
+	// interpolate in fixed point with 8 bit fraction
+	wb := uint32(t*256 + 0.5)
+	wa := 256 - wb
+	for i, v := range res.Pix {
+		res.Pix[i] = uint8((uint32(v)*wa + uint32(src.Pix[i])*wb + 128) >> 8)
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package StreamDeck
+
+import (
+	"image/color"
+	"time"
+)
+
+// BootAnim is an animation played when the panel becomes ready, before the
+// application takes over (see SetBootAnimation). It draws onto the buttons
+// and returns when it is finished.
+type BootAnim func(sd *StreamDeck)
+
+// bootColor is the color used by the built-in boot animations.
+var bootColor = color.RGBA{0, 120, 255, 255}
+
+// timing of the built-in boot animations
+const (
+	bootSweepStep   = 40 * time.Millisecond
+	bootFadeSteps   = 15
+	bootFadeStep    = 40 * time.Millisecond
+	bootFadeHoldFor = 200 * time.Millisecond
+)
+
+// BootSweep lights up the buttons one after another in reading order, from
+// the top left to the bottom right.
+func BootSweep(sd *StreamDeck) {
+	for pos := 0; pos < sd.model.NumButtons; pos++ {
+		btnIndex := sd.model.readingOrder(pos)
+		sd.FillColor(btnIndex, int(bootColor.R), int(bootColor.G), int(bootColor.B))
+		time.Sleep(bootSweepStep)
+	}
+}
+
+// BootFadeIn fills all buttons with a color and fades the display in from
+// black up to the configured brightness.
+func BootFadeIn(sd *StreamDeck) {
+	sd.writeMu.Lock()
+	brightness := sd.brightness
+	sd.sendBrightness(0)
+	sd.writeMu.Unlock()
+
+	for btnIndex := 0; btnIndex < sd.model.NumButtons; btnIndex++ {
+		sd.FillColor(btnIndex, int(bootColor.R), int(bootColor.G), int(bootColor.B))
+	}
+
+	for step := 1; step <= bootFadeSteps; step++ {
+		sd.writeMu.Lock()
+		sd.sendBrightness(brightness * step / bootFadeSteps)
+		sd.writeMu.Unlock()
+		time.Sleep(bootFadeStep)
+	}
+	time.Sleep(bootFadeHoldFor)
+
+	// the brightness may have been changed in the meantime
+	sd.writeMu.Lock()
+	sd.sendBrightness(sd.brightness)
+	sd.writeMu.Unlock()
+}
+
+// SetBootAnimation sets an animation which is played whenever the panel
+// becomes ready after it has been cleared, i.e. after a reconnect, before
+// the OnReady callback is executed. The built-in animations are BootSweep
+// and BootFadeIn; any function can be provided as well. If the panel is
+// already ready, like directly after NewStreamDeck, the animation is played
+// immediately and SetBootAnimation returns when it is finished, so that
+// content written afterwards isn't overwritten. The buttons are cleared
+// after the animation. A nil animation disables it, which is the default.
+func (sd *StreamDeck) SetBootAnimation(anim BootAnim) {
+	sd.Lock()
+	sd.bootAnim = anim
+	ready := sd.ready
+	sd.Unlock()
+
+	if ready {
+		sd.playBootAnimation()
+	}
+}
+
+// playBootAnimation plays the boot animation (if set) and clears the
+// buttons afterwards.
+func (sd *StreamDeck) playBootAnimation() {
+	sd.Lock()
+	anim := sd.bootAnim
+	sd.Unlock()
+
+	if anim == nil {
+		return
+	}
+	anim(sd)
+	sd.ClearAllBtns()
+}
@@ -0,0 +1,115 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// btnCache holds the content which is currently displayed on a button.
+type btnCache struct {
+	// img is the composited image with the size of a button
+	img *image.RGBA
+	// buf contains the encoded pixels as sent to the Stream Deck
+	buf []byte
+	// src describes how the content has been created (if known); it is
+	// used by ExportLayout
+	src *LayoutKey
+	// dirty forces the button to be written by the next batch even if the
+	// content is unchanged
+	dirty bool
+	// fill is set if the button has been filled with FillColor
+	fill *colorFill
+}
+
+// colorFill describes a button filled with a solid color, including the
+// settings which affect how the color is displayed.
+type colorFill struct {
+	color color.RGBA
+	mask  KeyMask
+	gamma *gammaLUT
+}
+
+// currentFill returns the colorFill of a color with the current settings.
+func (sd *StreamDeck) currentFill(c color.RGBA) colorFill {
+	sd.Lock()
+	defer sd.Unlock()
+	return colorFill{color: c, mask: sd.keyMask, gamma: sd.gamma}
+}
+
+// isFilledWith returns true if the button currently displays exactly the
+// given colorFill, so that writing it again can be skipped. The write lock
+// must be held by the caller.
+func (sd *StreamDeck) isFilledWith(btnIndex int, fill colorFill) bool {
+	c := sd.cache[btnIndex]
+	if c.fill == nil || c.dirty || c.buf == nil {
+		return false
+	}
+	if _, ok := sd.animations[btnIndex]; ok {
+		return false
+	}
+	return *c.fill == fill
+}
+
+// cachedImage returns a copy of the image currently displayed on a button
+// or nil if the content of the button is unknown. The write lock must be
+// held by the caller.
+func (sd *StreamDeck) cachedImage(btnIndex int) *image.RGBA {
+	if btnIndex < 0 || btnIndex >= len(sd.cache) || sd.cache[btnIndex].img == nil {
+		return nil
+	}
+	return copyRGBA(sd.cache[btnIndex].img)
+}
+
+// isCached returns true if the encoded pixels equal the content currently
+// displayed on the button. The write lock must be held by the caller.
+func (sd *StreamDeck) isCached(btnIndex int, imgBuf []byte) bool {
+	c := sd.cache[btnIndex]
+	return !c.dirty && c.buf != nil && bytes.Equal(c.buf, imgBuf)
+}
+
+// IsKeyDirty returns true if the next batch (see Frame and Framebuffer)
+// writes the button even if its content is unchanged. This is the case if
+// the content currently displayed on the button is unknown, e.g. after a
+// reconnect, or if the button has been marked with MarkKeyDirty.
+func (sd *StreamDeck) IsKeyDirty(btnIndex int) bool {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return false
+	}
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	c := sd.cache[btnIndex]
+	return c.dirty || c.buf == nil
+}
+
+// MarkKeyDirty forces the next batch to write the button, even if the
+// library considers its content unchanged. Applications which track the
+// displayed content themselves can use it to resend specific buttons. The
+// mark is cleared when the button is written.
+func (sd *StreamDeck) MarkKeyDirty(btnIndex int) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	sd.cache[btnIndex].dirty = true
+	return nil
+}
+
+// invalidateCache marks the content of all buttons as unknown, e.g. after
+// the device has been reconnected. The write lock must be held by the
+// caller.
+func (sd *StreamDeck) invalidateCache() {
+	for i := range sd.cache {
+		sd.cache[i] = btnCache{}
+	}
+}
+
+// copyRGBA returns a copy of img as *image.RGBA with its origin at (0,0).
+func copyRGBA(img image.Image) *image.RGBA {
+	rect := img.Bounds()
+	res := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(res, res.Bounds(), img, rect.Min, draw.Src)
+	return res
+}
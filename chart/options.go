@@ -0,0 +1,55 @@
+package chart
+
+import (
+	"image/color"
+)
+
+// Window is a functional option which sets the amount of values shown by
+// the Sparkline. The default is 30 values.
+func Window(n int) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.window = n
+	}
+}
+
+// ChartStyle is a functional option which sets whether the values are
+// drawn as Line (default) or Bar chart.
+func ChartStyle(style Style) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.style = style
+	}
+}
+
+// LineColor is a functional option which sets the color of the line or
+// bars.
+func LineColor(c color.Color) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.lineColor = c
+	}
+}
+
+// BgColor is a functional option which sets the background color.
+func BgColor(c color.Color) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.bgColor = c
+	}
+}
+
+// Range is a functional option which fixes the range of the vertical axis.
+// Values outside of the range are clipped. By default the range adapts to
+// the values within the window.
+func Range(min, max float64) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.fixedRange = true
+		s.min = min
+		s.max = max
+	}
+}
+
+// MaxFPS is a functional option which limits the rate of redraws. The
+// default is 10 frames per second; 0 redraws on every Push.
+func MaxFPS(fps int) func(*Sparkline) {
+	return func(s *Sparkline) {
+		s.maxFPS = fps
+	}
+}
@@ -0,0 +1,243 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+	"time"
+
+	sd "github.com/AKovalevich/streamdeck"
+)
+
+// Style determines how a Sparkline visualizes its values.
+type Style int
+
+const (
+	// Line connects the values with a line.
+	Line Style = iota
+	// Bar draws a vertical bar for each value.
+	Bar
+)
+
+// Sparkline is a small chart showing the most recent values pushed to it.
+// The values are kept in a rolling window and the chart is scaled to the
+// key. Unless a fixed range has been set, the vertical axis adapts to the
+// minimum and maximum values within the window. Redraws are throttled to a
+// maximum frame rate.
+type Sparkline struct {
+	sync.Mutex
+	streamDeck *sd.StreamDeck
+	id         int
+	values     []float64
+	window     int
+	style      Style
+	lineColor  color.Color
+	bgColor    color.Color
+	fixedRange bool
+	min        float64
+	max        float64
+	maxFPS     int
+	lastDraw   time.Time
+	pending    *time.Timer
+}
+
+// NewSparkline is the constructor of a Sparkline. Functional arguments can
+// be supplied to modify its default characteristics.
+func NewSparkline(sd *sd.StreamDeck, btnIndex int, options ...func(*Sparkline)) (*Sparkline, error) {
+	if sd == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+
+	s := &Sparkline{
+		streamDeck: sd,
+		id:         btnIndex,
+		window:     30,
+		style:      Line,
+		lineColor:  color.RGBA{0, 200, 255, 255},
+		bgColor:    color.Black,
+		maxFPS:     10,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.window < 2 {
+		return nil, fmt.Errorf("window must contain at least 2 values")
+	}
+	if s.fixedRange && s.max <= s.min {
+		return nil, fmt.Errorf("invalid range [%v, %v]", s.min, s.max)
+	}
+
+	return s, nil
+}
+
+// Push adds a value to the rolling window and redraws the chart. If the
+// last redraw happened less than a frame ago, the redraw is deferred.
+func (s *Sparkline) Push(value float64) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.values = append(s.values, value)
+	if len(s.values) > s.window {
+		s.values = s.values[len(s.values)-s.window:]
+	}
+
+	if s.pending != nil {
+		// the deferred redraw will pick up the value
+		return
+	}
+
+	wait := time.Duration(0)
+	if s.maxFPS > 0 {
+		wait = s.lastDraw.Add(time.Second / time.Duration(s.maxFPS)).Sub(time.Now())
+	}
+	if wait <= 0 {
+		s.draw()
+		return
+	}
+
+	s.pending = time.AfterFunc(wait, func() {
+		s.Lock()
+		defer s.Unlock()
+		s.pending = nil
+		s.draw()
+	})
+}
+
+// Values returns a copy of the values within the rolling window.
+func (s *Sparkline) Values() []float64 {
+	s.Lock()
+	defer s.Unlock()
+	values := make([]float64, len(s.values))
+	copy(values, s.values)
+	return values
+}
+
+// Draw renders the Sparkline on the designated button.
+func (s *Sparkline) Draw() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.streamDeck.DrawKey(s.id, s.render)
+}
+
+// Close cancels a deferred redraw.
+func (s *Sparkline) Close() {
+	s.Lock()
+	defer s.Unlock()
+	if s.pending != nil {
+		s.pending.Stop()
+		s.pending = nil
+	}
+}
+
+// draw renders the chart. The lock must be held by the caller.
+func (s *Sparkline) draw() {
+	s.lastDraw = time.Now()
+	if err := s.streamDeck.DrawKey(s.id, s.render); err != nil {
+		s.streamDeck.Log().Warn(err.Error())
+	}
+}
+
+// valueRange returns the range of the vertical axis. The lock must be held
+// by the caller.
+func (s *Sparkline) valueRange() (float64, float64) {
+	if s.fixedRange {
+		return s.min, s.max
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range s.values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if max-min < 1e-9 {
+		// constant values are drawn in the middle
+		return min - 1, max + 1
+	}
+	return min, max
+}
+
+// render draws the chart onto dst. The lock must be held by the caller.
+func (s *Sparkline) render(dst *image.RGBA) {
+	rect := dst.Bounds()
+	draw.Draw(dst, rect, image.NewUniform(s.bgColor), image.Point{0, 0}, draw.Src)
+
+	if len(s.values) == 0 {
+		return
+	}
+
+	// the canvas may be supersampled; scale the line width accordingly
+	factor := rect.Dx() / s.streamDeck.Capabilities().KeySize
+	if factor < 1 {
+		factor = 1
+	}
+	margin := 4 * factor
+	width := rect.Dx() - 2*margin
+	height := rect.Dy() - 2*margin
+	min, max := s.valueRange()
+
+	// y returns the vertical position of a value
+	y := func(v float64) float64 {
+		v = math.Max(min, math.Min(max, v))
+		return float64(rect.Min.Y+margin) + (max-v)/(max-min)*float64(height)
+	}
+
+	src := image.NewUniform(s.lineColor)
+
+	switch s.style {
+	case Bar:
+		barWidth := float64(width) / float64(s.window)
+		// the newest value is located at the right border
+		offset := s.window - len(s.values)
+		for i, v := range s.values {
+			x0 := rect.Min.X + margin + int(float64(i+offset)*barWidth)
+			x1 := rect.Min.X + margin + int(float64(i+offset+1)*barWidth) - factor
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			top := int(y(v))
+			draw.Draw(dst, image.Rect(x0, top, x1, rect.Max.Y-margin), src, image.Point{0, 0}, draw.Src)
+		}
+	default:
+		step := float64(width) / float64(s.window-1)
+		offset := s.window - len(s.values)
+		thickness := factor + 1
+		prevX, prevY := -1, 0
+		for i, v := range s.values {
+			x := rect.Min.X + margin + int(float64(i+offset)*step)
+			cy := int(y(v))
+			if prevX < 0 {
+				prevX, prevY = x, cy
+			}
+			drawSegment(dst, prevX, prevY, x, cy, thickness, src)
+			prevX, prevY = x, cy
+		}
+	}
+}
+
+// drawSegment draws a line with the given thickness from (x0, y0) to
+// (x1, y1) by filling a vertical span for every column.
+func drawSegment(dst *image.RGBA, x0, y0, x1, y1, thickness int, src image.Image) {
+	half := thickness / 2
+	if x0 == x1 {
+		top, bottom := y0, y1
+		if top > bottom {
+			top, bottom = bottom, top
+		}
+		draw.Draw(dst, image.Rect(x0-half, top-half, x0-half+thickness, bottom-half+thickness), src, image.Point{0, 0}, draw.Src)
+		return
+	}
+	prev := y0
+	for x := x0; x <= x1; x++ {
+		cur := y0 + (y1-y0)*(x-x0)/(x1-x0)
+		top, bottom := prev, cur
+		if top > bottom {
+			top, bottom = bottom, top
+		}
+		draw.Draw(dst, image.Rect(x-half, top-half, x-half+thickness, bottom-half+thickness), src, image.Point{0, 0}, draw.Src)
+		prev = cur
+	}
+}
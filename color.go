@@ -0,0 +1,35 @@
+package StreamDeck
+
+import (
+	"image/color"
+	"math"
+)
+
+// ContrastColor returns black or white, whichever is better legible on the
+// background color bg. The decision is based on the relative luminance of
+// bg as defined by WCAG 2.0.
+func ContrastColor(bg color.Color) color.Color {
+	// with a luminance above this threshold, black text yields a higher
+	// contrast ratio than white text
+	const threshold = 0.179
+
+	if relativeLuminance(bg) > threshold {
+		return color.Black
+	}
+	return color.White
+}
+
+// relativeLuminance returns the relative luminance (0 to 1) of c.
+func relativeLuminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return 0.2126*linearize(r) + 0.7152*linearize(g) + 0.0722*linearize(b)
+}
+
+// linearize converts a 16 bit sRGB channel value into linear light.
+func linearize(v uint32) float64 {
+	c := float64(v) / 0xffff
+	if c <= 0.03928 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
@@ -0,0 +1,95 @@
+package StreamDeck
+
+import (
+	"image"
+	"time"
+)
+
+// SetConfirmAction configures a button for destructive actions which have
+// to be confirmed: the first press arms the button and shows armedImg on
+// top of its content (see OverlayKey). A second press within timeout
+// restores the content and executes action; without it, the button is
+// disarmed when the timeout expires. The action is executed in its own
+// goroutine. Calling SetConfirmAction again for the same button replaces
+// the configuration, a nil action removes it. Invalid arguments are logged
+// and ignored.
+func (sd *StreamDeck) SetConfirmAction(btnIndex int, armedImg image.Image, timeout time.Duration, action func()) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		sd.log.Warn(err.Error())
+		return
+	}
+	if action != nil && armedImg == nil {
+		sd.log.Warn("confirm action requires an image for the armed state")
+		return
+	}
+	if action != nil && timeout <= 0 {
+		sd.log.Warn("timeout of confirm action must be positive")
+		return
+	}
+
+	sd.Lock()
+	cancel := sd.confirmCancels[btnIndex]
+	delete(sd.confirmCancels, btnIndex)
+	sd.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	if action == nil {
+		return
+	}
+
+	events, cancel := sd.Subscribe()
+	sd.Lock()
+	if sd.confirmCancels == nil {
+		sd.confirmCancels = make(map[int]func())
+	}
+	sd.confirmCancels[btnIndex] = cancel
+	sd.Unlock()
+
+	go sd.runConfirmAction(events, btnIndex, armedImg, timeout, action)
+}
+
+// runConfirmAction implements the state machine of a confirm action until
+// the subscription is cancelled.
+func (sd *StreamDeck) runConfirmAction(events <-chan Event, btnIndex int, armedImg image.Image, timeout time.Duration, action func()) {
+	var restore func() error
+	var timer *time.Timer
+	var expired <-chan time.Time
+
+	disarm := func() {
+		if restore == nil {
+			return
+		}
+		timer.Stop()
+		if err := restore(); err != nil {
+			sd.log.Warn(err.Error())
+		}
+		restore, timer, expired = nil, nil, nil
+	}
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				// the subscription has been cancelled
+				disarm()
+				return
+			}
+			if ev.BtnIndex != btnIndex || ev.State != BtnPressed {
+				continue
+			}
+			if restore == nil {
+				restore = sd.OverlayKey(btnIndex, armedImg)
+				timer = time.NewTimer(timeout)
+				expired = timer.C
+				continue
+			}
+			disarm()
+			go action()
+		case <-expired:
+			disarm()
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// supportedMediaTypes are the media types of data URIs accepted by
+// FillImageFromDataURI. They correspond to the registered image decoders.
+var supportedMediaTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+// FillImageFromDataURI fills the given key with an image embedded in a data
+// URI, like "data:image/png;base64,iVBORw0...". PNG, JPEG and GIF images are
+// supported. This allows to embed small icons inline, e.g. in configuration
+// files.
+func (sd *StreamDeck) FillImageFromDataURI(btnIndex int, uri string) error {
+	data, err := parseDataURI(uri)
+	if err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to decode image of data uri: %v", err)
+	}
+
+	if err := sd.FillImage(btnIndex, img); err != nil {
+		return err
+	}
+	sd.setKeySource(btnIndex, LayoutKey{Image: uri})
+	return nil
+}
+
+// parseDataURI validates a data URI with an image media type and returns
+// the decoded payload.
+func parseDataURI(uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return nil, fmt.Errorf("malformed data uri: missing \"data:\" scheme")
+	}
+
+	comma := strings.IndexByte(uri, ',')
+	if comma < 0 {
+		return nil, fmt.Errorf("malformed data uri: missing \",\" in front of the data")
+	}
+	header, payload := uri[len("data:"):comma], uri[comma+1:]
+
+	isBase64 := false
+	if strings.HasSuffix(header, ";base64") {
+		isBase64 = true
+		header = strings.TrimSuffix(header, ";base64")
+	}
+
+	if header == "" {
+		return nil, fmt.Errorf("data uri without media type; an image media type is required")
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, fmt.Errorf("malformed media type in data uri: %v", err)
+	}
+	if !supportedMediaTypes[mediaType] {
+		return nil, fmt.Errorf("unsupported media type %q in data uri", mediaType)
+	}
+
+	if !isBase64 {
+		data, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, fmt.Errorf("malformed data in data uri: %v", err)
+		}
+		return []byte(data), nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("malformed base64 data in data uri: %v", err)
+	}
+	return data, nil
+}
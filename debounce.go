@@ -0,0 +1,81 @@
+package StreamDeck
+
+import (
+	"time"
+)
+
+// debounceState tracks the raw input of a button while it is debounced.
+type debounceState struct {
+	// raw is the last state reported by the device
+	raw BtnState
+	// timer delivers the final state once the button has settled
+	timer *time.Timer
+}
+
+// SetDebounce suppresses state changes of a button until its state has not
+// changed for d, e.g. to filter phantom flickers of a worn key. Only the
+// stable final state is passed on, once d has elapsed after the last
+// change; a flicker which returns to the previous state within d produces
+// no events at all. Passing the first change on immediately would react
+// faster, but would dispatch the phantom press of a released key, which is
+// what debouncing has to prevent. The price is that every event is delayed
+// by d. Unlike SetEventThrottle, ButtonStates reflects the debounced state.
+// A duration of zero (the default) disables the debouncing.
+func (sd *StreamDeck) SetDebounce(d time.Duration) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.debounce = d
+}
+
+// debounced records the state reported by the device for a button and
+// returns true if a change to it has to be suppressed until the button has
+// settled. The lock must be held by the caller.
+func (sd *StreamDeck) debounced(btnIndex int, state BtnState) bool {
+	if sd.debounce <= 0 {
+		return false
+	}
+	if sd.debounceStates == nil {
+		sd.debounceStates = make([]debounceState, len(sd.btnState))
+		for i := range sd.debounceStates {
+			sd.debounceStates[i].raw = sd.btnState[i]
+		}
+	}
+
+	s := &sd.debounceStates[btnIndex]
+	if state != s.raw {
+		// every change restarts the settle time
+		s.raw = state
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.timer = time.AfterFunc(sd.debounce, func() {
+			sd.settle(btnIndex)
+		})
+	}
+	return state != sd.btnState[btnIndex]
+}
+
+// settle passes on the final state of a debounced button if it differs
+// from the last state passed on.
+func (sd *StreamDeck) settle(btnIndex int) {
+	now := time.Now()
+
+	sd.Lock()
+	s := &sd.debounceStates[btnIndex]
+	wasPressed := sd.anyPressed()
+	var events []Event
+	if s.raw != sd.btnState[btnIndex] {
+		if ev, ok := sd.applyBtnState(btnIndex, s.raw, now); ok {
+			events = append(events, ev)
+		}
+	}
+	allReleased := wasPressed && !sd.anyPressed()
+	sd.Unlock()
+
+	for _, ev := range sd.screensaverActivity(events) {
+		sd.dispatch(ev)
+	}
+	if allReleased {
+		sd.dispatchAllReleased()
+	}
+}
@@ -0,0 +1,77 @@
+package StreamDeck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	const d = 50 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		presses []bool
+		want    []BtnState
+	}{
+		{"flicker of a released key", []bool{true, false}, nil},
+		{"press with bounce", []bool{true, false, true}, []BtnState{BtnPressed}},
+		{"press and release", []bool{true, false, true, false}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, vd := newTestDeck(t, ProductID)
+			sd.SetDebounce(d)
+
+			events := make(chan Event, 10)
+			sd.SetBtnEventCb(func(btnIndex int, state BtnState) {
+				events <- Event{BtnIndex: btnIndex, State: state}
+			})
+			stop := serve(t, sd)
+			defer stop()
+
+			// all changes occur within d
+			for _, pressed := range tt.presses {
+				var err error
+				if pressed {
+					err = vd.Press(4)
+				} else {
+					err = vd.Release(4)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []BtnState
+			timeout := time.After(4 * d)
+		collect:
+			for {
+				select {
+				case ev := <-events:
+					if ev.BtnIndex != 4 {
+						t.Errorf("event for key %d, want key 4", ev.BtnIndex)
+					}
+					got = append(got, ev.State)
+				case <-timeout:
+					break collect
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got events %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got events %v, want %v", got, tt.want)
+				}
+			}
+			final := BtnReleased
+			if len(tt.want) > 0 {
+				final = tt.want[len(tt.want)-1]
+			}
+			if state := sd.ButtonStates()[4]; state != final {
+				t.Errorf("state after settling = %v, want %v", state, final)
+			}
+		})
+	}
+}
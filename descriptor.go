@@ -0,0 +1,111 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/gousb"
+)
+
+// DumpDescriptor writes the USB descriptors of all connected Elgato devices
+// to w, including the ones of models which are not (yet) supported. The
+// output contains the product and vendor IDs, the string descriptors and
+// the details of all configurations, interfaces and endpoints, which is
+// the information needed to add support for a new model. The devices are
+// opened for reading the string descriptors, but their interfaces are not
+// claimed.
+func DumpDescriptor(w io.Writer) error {
+	ctx := gousb.NewContext()
+	defer ctx.Close()
+
+	devices, err := ctx.OpenDevices(func(desc *gousb.DeviceDesc) bool {
+		return desc.Vendor == gousb.ID(VendorID)
+	})
+	defer func() {
+		for _, d := range devices {
+			d.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		_, err := fmt.Fprintf(w, "no Elgato devices (vendor id 0x%04x) found\n", VendorID)
+		return err
+	}
+
+	for i, d := range devices {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := dumpDevice(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpDevice writes the descriptors of an opened device to w.
+func dumpDevice(w io.Writer, d *gousb.Device) error {
+	desc := d.Desc
+
+	model := "unsupported model"
+	if m, err := modelForProductID(uint16(desc.Product)); err == nil {
+		model = m.Name
+	}
+
+	// string descriptors may be unavailable, e.g. due to missing permissions
+	str := func(s string, err error) string {
+		if err != nil {
+			return fmt.Sprintf("<%v>", err)
+		}
+		return s
+	}
+
+	fmt.Fprintf(w, "Device %s (%s)\n", usbPath(desc), model)
+	fmt.Fprintf(w, "  VendorID:      0x%04x\n", uint16(desc.Vendor))
+	fmt.Fprintf(w, "  ProductID:     0x%04x\n", uint16(desc.Product))
+	fmt.Fprintf(w, "  Manufacturer:  %s\n", str(d.Manufacturer()))
+	fmt.Fprintf(w, "  Product:       %s\n", str(d.Product()))
+	fmt.Fprintf(w, "  SerialNumber:  %s\n", str(d.SerialNumber()))
+	fmt.Fprintf(w, "  USB Spec:      %v\n", desc.Spec)
+	fmt.Fprintf(w, "  Device Rev:    %v\n", desc.Device)
+	fmt.Fprintf(w, "  Speed:         %v\n", desc.Speed)
+	fmt.Fprintf(w, "  Class:         %v / %v / %v\n", desc.Class, desc.SubClass, desc.Protocol)
+	fmt.Fprintf(w, "  MaxCtrlPacket: %d\n", desc.MaxControlPacketSize)
+
+	cfgNums := make([]int, 0, len(desc.Configs))
+	for num := range desc.Configs {
+		cfgNums = append(cfgNums, num)
+	}
+	sort.Ints(cfgNums)
+
+	for _, num := range cfgNums {
+		cfg := desc.Configs[num]
+		fmt.Fprintf(w, "  Config %d: self powered %v, remote wakeup %v, max power %v\n",
+			cfg.Number, cfg.SelfPowered, cfg.RemoteWakeup, cfg.MaxPower)
+
+		for _, intf := range cfg.Interfaces {
+			for _, alt := range intf.AltSettings {
+				fmt.Fprintf(w, "    Interface %d alt %d: class %v / %v / %v\n",
+					alt.Number, alt.Alternate, alt.Class, alt.SubClass, alt.Protocol)
+
+				addrs := make([]int, 0, len(alt.Endpoints))
+				for addr := range alt.Endpoints {
+					addrs = append(addrs, int(addr))
+				}
+				sort.Ints(addrs)
+
+				for _, addr := range addrs {
+					ep := alt.Endpoints[gousb.EndpointAddress(addr)]
+					fmt.Fprintf(w, "      Endpoint 0x%02x: %v %v, max packet size %d, poll interval %v\n",
+						addr, ep.Direction, ep.TransferType, ep.MaxPacketSize, ep.PollInterval)
+				}
+			}
+		}
+	}
+
+	return nil
+}
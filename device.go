@@ -1,25 +1,109 @@
 package StreamDeck
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/gousb"
 )
 
+// Device is the transport used to communicate with a Stream Deck. USBDevice
+// is the implementation for real hardware. Other implementations (e.g.
+// VirtualDevice) can be supplied through NewStreamDeckWithDevice.
+type Device interface {
+	Connect() error
+	Close() error
+	IsConnected() bool
+	GetSerialNumber() (string, error)
+	GetProductID() uint16
+	GetVendorID() uint16
+	// Read reads an input report from the device.
+	Read(data []byte) (int, error)
+	// Write writes an output report to the device.
+	Write(data []byte) (int, error)
+	// SendFeatureReport sends a feature report to the device. The first
+	// byte must contain the report ID.
+	SendFeatureReport(data []byte) error
+}
+
+// ReportIDPrefixer can be implemented by a Device whose backend adds the
+// report ID in front of output reports itself, like some HID stacks do. If
+// PrefixesReportID returns true, the reports passed to Write start directly
+// with the payload; otherwise their first byte is the report ID. Without
+// the distinction, the report ID would be sent twice and the content of the
+// buttons would be shifted by one byte.
+type ReportIDPrefixer interface {
+	PrefixesReportID() bool
+}
+
+// FeatureReporter can be implemented by a Device which is able to read
+// feature reports, e.g. to query the firmware version (see DeviceInfo).
+// GetFeatureReport reads the feature report with the report ID in the first
+// byte of data into data and returns the amount of bytes read.
+type FeatureReporter interface {
+	GetFeatureReport(data []byte) (int, error)
+}
+
+// ContextReader can be implemented by a Device whose Read can be aborted.
+// ReadContext reads an input report like Read, but returns the error of ctx
+// as soon as ctx is done. It allows ServeContext to terminate its reading
+// goroutine without waiting for the next button event.
+type ContextReader interface {
+	ReadContext(ctx context.Context, data []byte) (int, error)
+}
+
+// readTimeouter is implemented by devices supporting read timeouts (see
+// StreamDeck.SetReadTimeout).
+type readTimeouter interface {
+	SetReadTimeout(timeout time.Duration)
+}
+
+// ReadCanceler can be implemented by a ContextReader which wraps another
+// Device (like RecordingDevice) to report whether its reads can actually be
+// aborted.
+type ReadCanceler interface {
+	CanCancelRead() bool
+}
+
+// canCancelRead returns true if a read of the device can be aborted with
+// ReadContext.
+func canCancelRead(device Device) bool {
+	if _, ok := device.(ContextReader); !ok {
+		return false
+	}
+	if c, ok := device.(ReadCanceler); ok {
+		return c.CanCancelRead()
+	}
+	return true
+}
+
 type USBDevice struct {
 	sync.Mutex
-	context     *gousb.Context
-	device      *gousb.Device
-	intf        *gousb.Interface
-	inEndpoint  *gousb.InEndpoint
-	outEndpoint *gousb.OutEndpoint
-	connected   bool
-	log         Logger
-	productID   uint16
-	vendorID    uint16
+	context       *gousb.Context
+	device        *gousb.Device
+	intf          *gousb.Interface
+	inEndpoint    *gousb.InEndpoint
+	outEndpoint   *gousb.OutEndpoint
+	connected     bool
+	log           Logger
+	productID     uint16
+	vendorID      uint16
+	usbPath       string
+	maxPacketSize int
+	// pathFilter selects the device by its USB path (if not empty)
+	pathFilter  string
+	readTimeout time.Duration
 }
 
+// ErrReadTimeout is returned by Read if no input report has been received
+// within the read timeout. The device remains connected.
+var ErrReadTimeout = errors.New("read timeout")
+
 func (usbDevice *USBDevice) IsConnected() bool {
 	usbDevice.Lock()
 	defer usbDevice.Unlock()
@@ -44,6 +128,25 @@ func (usbDevice *USBDevice) GetSerialNumber() (string, error) {
 	return usbDevice.device.SerialNumber()
 }
 
+// GetUSBPath returns the physical USB path of the device in the form
+// "<bus>-<port>.<port>...", e.g. "1-2.4" for a device connected to port 4
+// of a hub, which in turn is connected to port 2 of the root hub on bus 1.
+// The path is empty until the device is connected.
+func (usbDevice *USBDevice) GetUSBPath() string {
+	usbDevice.Lock()
+	defer usbDevice.Unlock()
+	return usbDevice.usbPath
+}
+
+// GetMaxPacketSize returns the maximum packet size (in bytes) of the OUT
+// endpoint. Output reports larger than a packet are split into several
+// packets. The size is 0 until the device is connected.
+func (usbDevice *USBDevice) GetMaxPacketSize() int {
+	usbDevice.Lock()
+	defer usbDevice.Unlock()
+	return usbDevice.maxPacketSize
+}
+
 func (usbDevice *USBDevice) SetConnected(connected bool) {
 	usbDevice.Lock()
 	usbDevice.connected = connected
@@ -64,19 +167,121 @@ func (usbDevice *USBDevice) Close() error {
 	return err
 }
 
-func (usbDevice *USBDevice) write(data []byte) (int, error) {
+func (usbDevice *USBDevice) Write(data []byte) (int, error) {
 	return usbDevice.outEndpoint.Write(data)
 }
 
-func (usbDevice *USBDevice) read(data []byte) (int, error) {
-	count, err := usbDevice.inEndpoint.Read(data)
+// HID class specific control requests
+const (
+	hidGetReport = 0x01
+	hidSetReport = 0x09
+)
+
+// HID report types
+const (
+	hidReportTypeFeature = 0x03
+)
+
+// SendFeatureReport sends a HID feature report to the device. The first
+// byte of data must contain the report ID.
+func (usbDevice *USBDevice) SendFeatureReport(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("feature report must contain at least the report ID")
+	}
+	if !usbDevice.IsConnected() {
+		return errors.New("device not connected")
+	}
+
+	rType := uint8(gousb.ControlOut | gousb.ControlClass | gousb.ControlInterface)
+	val := uint16(hidReportTypeFeature)<<8 | uint16(data[0])
+	idx := uint16(usbDevice.intf.Setting.Number)
+
+	n, err := usbDevice.device.Control(rType, hidSetReport, val, idx, data)
 	if err != nil {
-		usbDevice.SetConnected(false)
+		return err
 	}
+	if n != len(data) {
+		return fmt.Errorf("short feature report write: %d of %d bytes", n, len(data))
+	}
+	return nil
+}
 
+// GetFeatureReport reads a HID feature report from the device. The first
+// byte of data must contain the report ID; the report is read into data.
+func (usbDevice *USBDevice) GetFeatureReport(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errors.New("feature report must contain at least the report ID")
+	}
+	if !usbDevice.IsConnected() {
+		return 0, errors.New("device not connected")
+	}
+
+	rType := uint8(gousb.ControlIn | gousb.ControlClass | gousb.ControlInterface)
+	val := uint16(hidReportTypeFeature)<<8 | uint16(data[0])
+	idx := uint16(usbDevice.intf.Setting.Number)
+
+	return usbDevice.device.Control(rType, hidGetReport, val, idx, data)
+}
+
+// SetReadTimeout sets the maximum duration Read waits for an input report.
+// If it expires, Read returns ErrReadTimeout. A timeout of zero blocks
+// until a report is received, which is the default.
+func (usbDevice *USBDevice) SetReadTimeout(timeout time.Duration) {
+	usbDevice.Lock()
+	defer usbDevice.Unlock()
+	usbDevice.readTimeout = timeout
+}
+
+func (usbDevice *USBDevice) Read(data []byte) (int, error) {
+	return usbDevice.ReadContext(context.Background(), data)
+}
+
+// ReadContext reads an input report like Read. It returns the error of ctx
+// as soon as ctx is done; the device remains connected in that case.
+func (usbDevice *USBDevice) ReadContext(ctx context.Context, data []byte) (int, error) {
+	usbDevice.Lock()
+	timeout := usbDevice.readTimeout
+	usbDevice.Unlock()
+
+	if timeout <= 0 && ctx.Done() == nil {
+		count, err := usbDevice.inEndpoint.Read(data)
+		if err != nil {
+			usbDevice.SetConnected(false)
+		}
+		return count, err
+	}
+
+	readCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		readCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	count, err := usbDevice.inEndpoint.ReadContext(readCtx, data)
+	if err != nil && ctx.Err() != nil {
+		return count, ctx.Err()
+	}
+	if err != nil && count == 0 && readCtx.Err() == context.DeadlineExceeded {
+		return 0, ErrReadTimeout
+	}
+	if err != nil {
+		usbDevice.SetConnected(false)
+	}
 	return count, err
 }
 
+// newDefaultDevice returns the Device used by NewStreamDeck. It is replaced
+// by the HID backend if the package is built with the "hid" build tag.
+var newDefaultDevice = func(productID, vendorID uint16) Device {
+	return NewUSBDevice(productID, vendorID)
+}
+
+// newDeckDevice returns the Device used by NewStreamDeck for a deck found by
+// EnumerateDecks. It is replaced by the HID backend like newDefaultDevice.
+var newDeckDevice = func(deck DeckInfo) Device {
+	return NewUSBDeviceWithPath(deck.Model.ProductID, VendorID, deck.USBPath)
+}
+
 func NewUSBDevice(productID, vendorID uint16) *USBDevice {
 	return &USBDevice{
 		productID: productID,
@@ -85,20 +290,48 @@ func NewUSBDevice(productID, vendorID uint16) *USBDevice {
 	}
 }
 
+// NewUSBDeviceWithPath returns a USBDevice for the device with the given
+// product and vendor ID connected to the given USB path (see GetUSBPath).
+// It allows to select one of several identical Stream Decks.
+func NewUSBDeviceWithPath(productID, vendorID uint16, path string) *USBDevice {
+	usbDevice := NewUSBDevice(productID, vendorID)
+	usbDevice.pathFilter = path
+	return usbDevice
+}
+
 func (usbDevice *USBDevice) Connect() error {
 	ctx := gousb.NewContext()
-	devices, err := ctx.OpenDevices(findUSBDevice(usbDevice.productID, usbDevice.vendorID))
+	match := findUSBDevice(usbDevice.productID, usbDevice.vendorID)
+	if usbDevice.pathFilter != "" {
+		productMatch := match
+		match = func(desc *gousb.DeviceDesc) bool {
+			return productMatch(desc) && usbPath(desc) == usbDevice.pathFilter
+		}
+	}
+	devices, err := ctx.OpenDevices(match)
+	// only the first matching device is kept
+	for i, d := range devices {
+		if i > 0 || err != nil {
+			d.Close()
+		}
+	}
 	if err != nil {
+		ctx.Close()
 		return err
 	}
 
 	if len(devices) <= 0 {
+		ctx.Close()
 		return errors.New("no one devices")
 	}
 
 	usbDevice.device = devices[0]
 	usbDevice.context = ctx
 
+	usbDevice.Lock()
+	usbDevice.usbPath = usbPath(usbDevice.device.Desc)
+	usbDevice.Unlock()
+
 	// Detach the device from whichever process already
 	// has it.
 	err = usbDevice.device.SetAutoDetach(true)
@@ -139,6 +372,9 @@ func (usbDevice *USBDevice) Connect() error {
 					}
 
 					usbDevice.outEndpoint = endpoint
+					usbDevice.Lock()
+					usbDevice.maxPacketSize = endpointDesc.MaxPacketSize
+					usbDevice.Unlock()
 				}
 			}
 		}
@@ -154,3 +390,15 @@ func findUSBDevice(product, vendor uint16) func(desc *gousb.DeviceDesc) bool {
 		return desc.Product == gousb.ID(product) && desc.Vendor == gousb.ID(vendor)
 	}
 }
+
+// usbPath returns the physical USB path of a device.
+func usbPath(desc *gousb.DeviceDesc) string {
+	ports := make([]string, 0, len(desc.Path))
+	for _, p := range desc.Path {
+		ports = append(ports, strconv.Itoa(p))
+	}
+	if len(ports) == 0 {
+		ports = append(ports, strconv.Itoa(desc.Port))
+	}
+	return fmt.Sprintf("%d-%s", desc.Bus, strings.Join(ports, "."))
+}
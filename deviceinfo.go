@@ -0,0 +1,97 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DeviceInfo describes the hardware of a Stream Deck.
+type DeviceInfo struct {
+	// Serial is the serial number of the device.
+	Serial string
+	// ProductID and VendorID are the USB IDs of the device.
+	ProductID uint16
+	VendorID  uint16
+	// Firmware is the firmware version reported by the device.
+	Firmware string
+	// Model is the human readable name of the detected model.
+	Model string
+}
+
+// DeviceInfo returns the serial number, USB IDs, firmware version and model
+// of the Stream Deck. The firmware version is read with a feature report,
+// so the device must be a FeatureReporter (like USBDevice).
+func (sd *StreamDeck) DeviceInfo() (DeviceInfo, error) {
+	serial, err := sd.device.GetSerialNumber()
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+
+	firmware, err := sd.firmwareVersion()
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+
+	return DeviceInfo{
+		Serial:    serial,
+		ProductID: sd.device.GetProductID(),
+		VendorID:  sd.device.GetVendorID(),
+		Firmware:  firmware,
+		Model:     sd.model.Name,
+	}, nil
+}
+
+// firmwareVersion reads the firmware version from the device.
+func (sd *StreamDeck) firmwareVersion() (string, error) {
+	d, ok := sd.device.(FeatureReporter)
+	if !ok {
+		return "", errors.New("device does not support reading feature reports")
+	}
+	if sd.model.featureReportSize == 0 {
+		return "", fmt.Errorf("reading the firmware version of the %s is not supported", sd.model.Name)
+	}
+
+	report := make([]byte, sd.model.featureReportSize)
+	report[0] = sd.model.firmwareReportID
+
+	sd.writeMu.Lock()
+	n, err := d.GetFeatureReport(report)
+	sd.writeMu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("unable to read firmware version: %v", err)
+	}
+	if n <= sd.model.firmwareOffset {
+		return "", fmt.Errorf("firmware version report too short: %d bytes", n)
+	}
+
+	version := report[sd.model.firmwareOffset:n]
+	if end := bytes.IndexByte(version, 0); end >= 0 {
+		version = version[:end]
+	}
+	return strings.TrimSpace(string(version)), nil
+}
+
+// ListDevices returns the serial numbers, USB IDs and models of all
+// connected Stream Decks of the supported models, e.g. to let the user pick
+// a deck before calling NewStreamDeck with its serial number. The devices
+// are found like with EnumerateDecks without being connected, so the
+// firmware versions are empty.
+func ListDevices() ([]DeviceInfo, error) {
+	decks, err := EnumerateDecks()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]DeviceInfo, 0, len(decks))
+	for _, deck := range decks {
+		infos = append(infos, DeviceInfo{
+			Serial:    deck.Serial,
+			ProductID: deck.Model.ProductID,
+			VendorID:  VendorID,
+			Model:     deck.Model.Name,
+		})
+	}
+	return infos, nil
+}
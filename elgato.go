@@ -0,0 +1,373 @@
+package StreamDeck
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// elgatoOpenChild is the action UUID of a folder in the Elgato software.
+const elgatoOpenChild = "com.elgato.streamdeck.profile.openchild"
+
+// elgatoMaxDepth limits the nesting of folders in an Elgato profile.
+const elgatoMaxDepth = 16
+
+// ElgatoProfile contains the keys of a profile exported by the Elgato
+// Stream Deck software (.streamDeckProfile), as parsed by
+// ParseElgatoProfile.
+type ElgatoProfile struct {
+	// Name is the name of the profile (or folder).
+	Name string
+	// Keys contains the keys of the (first page of the) profile, indexed
+	// by their position in reading order, counted from the top left (the
+	// column plus the row multiplied with the columns of the model). Empty
+	// keys are omitted.
+	Keys map[int]ElgatoKey
+	// Pages contains all pages of a profile with several pages, in their
+	// order. The first page corresponds to Keys.
+	Pages []*ElgatoProfile
+}
+
+// ElgatoKey is a key of an Elgato profile.
+type ElgatoKey struct {
+	// Title is the text shown on the key.
+	Title string
+	// Image is the custom image of the current state of the key; nil if
+	// the profile doesn't contain one.
+	Image image.Image
+	// Folder contains the keys of the folder opened by the key; nil if the
+	// key isn't a folder.
+	Folder *ElgatoProfile
+	// Column and Row are the position of the key, counted from the top
+	// left.
+	Column, Row int
+}
+
+// elgatoManifest is the manifest.json of a profile, page or folder. Older
+// versions of the Elgato software place the actions at the top level, newer
+// ones in the keypad controller and split them into pages.
+type elgatoManifest struct {
+	Name        string
+	Actions     map[string]elgatoAction
+	Controllers []struct {
+		Type    string
+		Actions map[string]elgatoAction
+	}
+	Pages struct {
+		Pages []string
+	}
+}
+
+type elgatoAction struct {
+	UUID     string
+	State    int
+	States   []elgatoState
+	Settings struct {
+		ProfileUUID string
+	}
+}
+
+type elgatoState struct {
+	Image string
+	Title string
+}
+
+// elgatoArchive provides case insensitive access to the files of a profile
+// archive.
+type elgatoArchive struct {
+	files map[string]*zip.File
+}
+
+// LoadElgatoProfile fills the buttons with the keys of a profile exported
+// by the Elgato Stream Deck software. See ParseElgatoProfile for the
+// supported content and ElgatoProfile.Fill for how the keys are drawn.
+func LoadElgatoProfile(sd *StreamDeck, path string) error {
+	profile, err := ParseElgatoProfile(path)
+	if err != nil {
+		return err
+	}
+	return profile.Fill(sd)
+}
+
+// ParseElgatoProfile reads a profile exported by the Elgato Stream Deck
+// software. The file is a zip archive containing a manifest.json with the
+// actions assigned to the key positions and the custom images of the keys.
+// Folders and pages are parsed recursively; they can be shown with Fill,
+// e.g. from a Page implementation. Only the titles and images of the keys
+// are imported, not their actions.
+func ParseElgatoProfile(file string) (*ElgatoProfile, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	a := &elgatoArchive{files: make(map[string]*zip.File)}
+	for _, f := range r.File {
+		a.files[strings.ToLower(f.Name)] = f
+	}
+
+	// the root profile is the manifest closest to the top of the archive
+	root := ""
+	found := false
+	for name := range a.files {
+		if path.Base(name) != "manifest.json" {
+			continue
+		}
+		dir := path.Dir(name)
+		if !found || strings.Count(dir, "/") < strings.Count(root, "/") {
+			root, found = dir, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no manifest.json found in elgato profile %s", file)
+	}
+
+	return a.parseProfile(root, 0)
+}
+
+// parseProfile parses the profile, page or folder located in dir.
+func (a *elgatoArchive) parseProfile(dir string, depth int) (*ElgatoProfile, error) {
+	if depth > elgatoMaxDepth {
+		return nil, fmt.Errorf("folders of elgato profile nested too deeply")
+	}
+
+	var m elgatoManifest
+	if err := a.readJSON(path.Join(dir, "manifest.json"), &m); err != nil {
+		return nil, err
+	}
+
+	profile := &ElgatoProfile{Name: m.Name}
+
+	if len(m.Pages.Pages) > 0 {
+		for _, id := range m.Pages.Pages {
+			pageDir, ok := a.findProfile(dir, id)
+			if !ok {
+				return nil, fmt.Errorf("page %s of elgato profile not found", id)
+			}
+			page, err := a.parseProfile(pageDir, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			profile.Pages = append(profile.Pages, page)
+		}
+		profile.Keys = profile.Pages[0].Keys
+		return profile, nil
+	}
+
+	actions := make(map[string]elgatoAction)
+	for pos, action := range m.Actions {
+		actions[pos] = action
+	}
+	for _, c := range m.Controllers {
+		if c.Type == "" || c.Type == "Keypad" {
+			for pos, action := range c.Actions {
+				actions[pos] = action
+			}
+		}
+	}
+
+	type keyPos struct {
+		col, row int
+		key      ElgatoKey
+	}
+	var keys []keyPos
+	columns := 0
+	for pos, action := range actions {
+		col, row, err := elgatoKeyPos(pos)
+		if err != nil {
+			return nil, err
+		}
+		key, err := a.parseKey(dir, pos, action, depth)
+		if err != nil {
+			return nil, err
+		}
+		key.Column, key.Row = col, row
+		keys = append(keys, keyPos{col, row, key})
+		if col >= columns {
+			columns = col + 1
+		}
+	}
+
+	// the profile doesn't contain its model, so the amount of columns is
+	// derived from the models supported by the library
+	columns = elgatoColumns(columns)
+	profile.Keys = make(map[int]ElgatoKey, len(keys))
+	for _, k := range keys {
+		profile.Keys[k.row*columns+k.col] = k.key
+	}
+
+	return profile, nil
+}
+
+// parseKey parses the action assigned to the key position pos.
+func (a *elgatoArchive) parseKey(dir, pos string, action elgatoAction, depth int) (ElgatoKey, error) {
+	var key ElgatoKey
+
+	state := action.State
+	if state < 0 || state >= len(action.States) {
+		state = 0
+	}
+	imgPath := ""
+	if state < len(action.States) {
+		key.Title = action.States[state].Title
+		if action.States[state].Image != "" {
+			imgPath = path.Join(dir, action.States[state].Image)
+		}
+	}
+	// older versions store the images in a folder per key position
+	if _, ok := a.files[strings.ToLower(imgPath)]; !ok {
+		imgPath = path.Join(dir, pos, "CustomImages", "state"+strconv.Itoa(state)+".png")
+	}
+
+	if f, ok := a.files[strings.ToLower(imgPath)]; ok {
+		img, err := decodeZipImage(f)
+		if err != nil {
+			return key, fmt.Errorf("unable to decode image of key %s in elgato profile: %v", pos, err)
+		}
+		key.Image = img
+	}
+
+	if action.UUID == elgatoOpenChild && action.Settings.ProfileUUID != "" {
+		if folderDir, ok := a.findProfile(dir, action.Settings.ProfileUUID); ok {
+			folder, err := a.parseProfile(folderDir, depth+1)
+			if err != nil {
+				return key, err
+			}
+			key.Folder = folder
+		}
+	}
+
+	return key, nil
+}
+
+// findProfile returns the directory of the sub-profile (folder or page)
+// with the given ID. Depending on the version of the Elgato software, it is
+// located next to or below the parent profile, with or without the
+// ".sdProfile" suffix.
+func (a *elgatoArchive) findProfile(parent, id string) (string, bool) {
+	id = strings.ToLower(id)
+	for name := range a.files {
+		if path.Base(name) != "manifest.json" {
+			continue
+		}
+		dir := path.Dir(name)
+		base := path.Base(dir)
+		if dir != parent && (base == id || base == id+".sdprofile") {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func (a *elgatoArchive) readJSON(name string, v interface{}) error {
+	f, ok := a.files[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%s not found in elgato profile", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("malformed %s in elgato profile: %v", name, err)
+	}
+	return nil
+}
+
+func decodeZipImage(f *zip.File) (image.Image, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(rc)
+	return img, err
+}
+
+// elgatoKeyPos parses a key position of the Elgato software ("column, row",
+// counted from the top left).
+func elgatoKeyPos(pos string) (col, row int, err error) {
+	parts := strings.Split(pos, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed key position %q in elgato profile", pos)
+	}
+	col, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed key position %q in elgato profile", pos)
+	}
+	row, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed key position %q in elgato profile", pos)
+	}
+	if col < 0 || row < 0 {
+		return 0, 0, fmt.Errorf("key position %q in elgato profile out of range", pos)
+	}
+	return col, row, nil
+}
+
+// elgatoColumns returns the amount of columns of the smallest model with at
+// least the given amount of columns.
+func elgatoColumns(minColumns int) int {
+	columns := 0
+	for _, m := range models {
+		if m.columns >= minColumns && (columns == 0 || m.columns < columns) {
+			columns = m.columns
+		}
+	}
+	if columns == 0 {
+		return minColumns
+	}
+	return columns
+}
+
+// Fill draws the keys of the profile onto the buttons. The image of a key
+// is scaled to fit the button and its title is drawn centered on top of it
+// with the embedded default font. Buttons without a key are cleared; keys
+// located outside of the panel of the model are omitted.
+func (p *ElgatoProfile) Fill(sd *StreamDeck) error {
+	keys := make(map[int]ElgatoKey, len(p.Keys))
+	for _, key := range p.Keys {
+		if key.Column < sd.model.columns && key.Row < sd.model.rows {
+			keys[sd.model.readingOrder(key.Row*sd.model.columns+key.Column)] = key
+		}
+	}
+
+	for btnIndex := 0; btnIndex < sd.model.NumButtons; btnIndex++ {
+		key, ok := keys[btnIndex]
+		if !ok || (key.Image == nil && key.Title == "") {
+			if err := sd.ClearBtn(btnIndex); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var textErr error
+		err := sd.DrawKey(btnIndex, func(dst *image.RGBA) {
+			draw.Draw(dst, dst.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+			if key.Image != nil {
+				size := dst.Bounds().Dx()
+				img := sd.scale(key.Image, size, size, ScaleFit)
+				draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+			}
+			if key.Title != "" {
+				factor := dst.Bounds().Dx() / sd.model.keySize
+				textErr = drawCenteredText(dst, key.Title, image.NewUniform(color.White), factor)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if textErr != nil {
+			return textErr
+		}
+	}
+	return nil
+}
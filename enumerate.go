@@ -0,0 +1,169 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/gousb"
+)
+
+// DeckInfo describes a Stream Deck found by EnumerateDecks.
+type DeckInfo struct {
+	// Serial is the serial number of the device.
+	Serial string
+	// Model is the detected model.
+	Model Model
+	// USBPath is the physical USB path of the device (see
+	// USBDevice.GetUSBPath).
+	USBPath string
+	// ID identifies the deck uniquely. It is the serial number, unless the
+	// serial number is empty or reported by several decks; then a synthetic
+	// ID derived from the USB path is used, which remains stable as long as
+	// the deck stays plugged into the same port. Pass it to NewStreamDeck
+	// to select the deck.
+	ID string
+}
+
+// syntheticIDPrefix is the prefix of the IDs of decks without a unique
+// serial number.
+const syntheticIDPrefix = "usb:"
+
+// EnumerateDecks returns all connected Stream Decks of the supported
+// models. The devices are opened for reading their serial numbers, but
+// their interfaces are not claimed, so decks in use by other processes are
+// listed as well. The decks are sorted by USB path. Decks with an empty or
+// duplicate serial number are reported with a warning on the standard
+// logger; they have to be selected by USB path (see DeckInfo.ID).
+func EnumerateDecks() ([]DeckInfo, error) {
+	return enumerateDecks(NewStdLogger())
+}
+
+// enumerateDecks implements EnumerateDecks, logging warnings to logger.
+func enumerateDecks(logger Logger) ([]DeckInfo, error) {
+	ctx := gousb.NewContext()
+	defer ctx.Close()
+
+	devices, err := ctx.OpenDevices(func(desc *gousb.DeviceDesc) bool {
+		if desc.Vendor != gousb.ID(VendorID) {
+			return false
+		}
+		_, err := modelForProductID(uint16(desc.Product))
+		return err == nil
+	})
+	defer func() {
+		for _, d := range devices {
+			d.Close()
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	decks := make([]DeckInfo, 0, len(devices))
+	for _, d := range devices {
+		model, err := modelForProductID(uint16(d.Desc.Product))
+		if err != nil {
+			continue
+		}
+		serial, err := d.SerialNumber()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read serial number of stream deck at %s: %v",
+				usbPath(d.Desc), err)
+		}
+		decks = append(decks, DeckInfo{
+			Serial:  serial,
+			Model:   model,
+			USBPath: usbPath(d.Desc),
+		})
+	}
+
+	sort.Slice(decks, func(i, j int) bool {
+		return decks[i].USBPath < decks[j].USBPath
+	})
+
+	assignDeckIDs(logger, decks)
+
+	return decks, nil
+}
+
+// assignDeckIDs sets the IDs of the decks. Decks with an empty or duplicate
+// serial number get a synthetic ID based on their USB path.
+func assignDeckIDs(logger Logger, decks []DeckInfo) {
+	count := make(map[string]int, len(decks))
+	for _, deck := range decks {
+		count[deck.Serial]++
+	}
+
+	for i := range decks {
+		deck := &decks[i]
+		switch {
+		case deck.Serial == "":
+			logger.Warn(fmt.Sprintf("stream deck at %s reports an empty serial number", deck.USBPath))
+		case count[deck.Serial] > 1:
+			logger.Warn(fmt.Sprintf("serial number %s is reported by %d stream decks (this one at %s)",
+				deck.Serial, count[deck.Serial], deck.USBPath))
+		default:
+			deck.ID = deck.Serial
+			continue
+		}
+		deck.ID = syntheticIDPrefix + deck.USBPath
+	}
+}
+
+// DeckErrors contains the errors which occurred in ForEachDevice, indexed
+// by the USB path of the deck.
+type DeckErrors map[string]error
+
+func (e DeckErrors) Error() string {
+	paths := make([]string, 0, len(e))
+	for path := range e {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	msgs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		msgs = append(msgs, fmt.Sprintf("deck %s: %v", path, e[path]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ForEachDevice connects to every Stream Deck found by EnumerateDecks one
+// after another, executes fn and closes the deck again. A deck which fails
+// doesn't prevent fn from being executed on the others; all errors are
+// returned as DeckErrors. Note that closing clears the buttons, unless fn
+// disables it with SetClearOnClose.
+func ForEachDevice(logger Logger, fn func(sd *StreamDeck) error) error {
+	if logger == nil {
+		logger = NewStdLogger()
+	}
+	decks, err := enumerateDecks(logger)
+	if err != nil {
+		return err
+	}
+
+	errs := make(DeckErrors)
+	for _, deck := range decks {
+		device := NewUSBDeviceWithPath(deck.Model.ProductID, VendorID, deck.USBPath)
+		sd, err := NewStreamDeckWithDevice(logger, device)
+		if err != nil {
+			device.Close()
+			errs[deck.USBPath] = err
+			continue
+		}
+
+		err = fn(sd)
+		if closeErr := sd.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			errs[deck.USBPath] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
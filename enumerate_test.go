@@ -0,0 +1,53 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"testing"
+)
+
+// warnLogger is a Logger which counts the warnings.
+type warnLogger struct {
+	StdLogger
+	warnings int
+}
+
+func (l *warnLogger) Warn(args ...interface{}) {
+	l.warnings++
+}
+
+func (l *warnLogger) Warnf(format string, args ...interface{}) {
+	l.Warn(fmt.Sprintf(format, args...))
+}
+
+func TestAssignDeckIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		serials  []string
+		want     []string
+		warnings int
+	}{
+		{"empty serial", []string{""}, []string{"usb:1-1"}, 1},
+		{"duplicate serial", []string{"AL00", "AL00"}, []string{"usb:1-1", "usb:1-2"}, 2},
+		{"unique serial", []string{"AL01", "AL02"}, []string{"AL01", "AL02"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decks := make([]DeckInfo, len(tt.serials))
+			for i, serial := range tt.serials {
+				decks[i] = DeckInfo{Serial: serial, Model: modelOriginal, USBPath: fmt.Sprintf("1-%d", i+1)}
+			}
+
+			l := &warnLogger{}
+			assignDeckIDs(l, decks)
+
+			for i, deck := range decks {
+				if deck.ID != tt.want[i] {
+					t.Errorf("ID of deck %d = %q, want %q", i, deck.ID, tt.want[i])
+				}
+			}
+			if l.warnings != tt.warnings {
+				t.Errorf("got %d warnings, want %d", l.warnings, tt.warnings)
+			}
+		})
+	}
+}
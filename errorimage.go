@@ -0,0 +1,51 @@
+package StreamDeck
+
+import (
+	"image"
+)
+
+// SetErrorImage sets an image which is shown on a button when writing its
+// content to the Stream Deck failed, instead of leaving the button with a
+// stale or partially written image. Showing the error image is a best
+// effort; it fails as well if the device has gone away. The image is
+// scaled like in FillImage and encoded with the settings (background, mask,
+// gamma, rotation) active when it is shown. A nil image disables the error
+// image, which is the default.
+func (sd *StreamDeck) SetErrorImage(img image.Image) {
+	if img != nil {
+		sd.Lock()
+		scaleMode := sd.scaleMode
+		sd.Unlock()
+
+		rect := img.Bounds()
+		if rect.Dx() != sd.model.keySize || rect.Dy() != sd.model.keySize {
+			img = sd.scale(img, sd.model.keySize, sd.model.keySize, scaleMode)
+		}
+	}
+
+	sd.Lock()
+	defer sd.Unlock()
+	sd.errorImage = img
+}
+
+// showErrorImage tries to show the error image on a button after a failed
+// write. The content of the button is unknown afterwards, unless the error
+// image has been written successfully. The write lock must be held by the
+// caller.
+func (sd *StreamDeck) showErrorImage(btnIndex int) {
+	sd.cache[btnIndex] = btnCache{}
+	delete(sd.badgeBase, btnIndex)
+
+	sd.Lock()
+	img := sd.errorImage
+	sd.Unlock()
+	if img == nil {
+		return
+	}
+
+	rgba, buf := sd.encodeBtnImage(btnIndex, img)
+	if err := sd.sendBtnBuf(btnIndex, buf); err != nil {
+		return
+	}
+	sd.cache[btnIndex] = btnCache{img: rgba, buf: buf}
+}
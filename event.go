@@ -0,0 +1,91 @@
+package StreamDeck
+
+import (
+	"context"
+	"time"
+)
+
+// eventBufferSize is the capacity of the channel returned by Subscribe.
+const eventBufferSize = 32
+
+// Event describes the change of a button state.
+type Event struct {
+	BtnIndex int
+	State    BtnState
+	Time     time.Time
+}
+
+// Subscribe returns a channel which receives all button events. Every
+// subscriber receives every event, independently of the BtnEvent callback
+// and other subscribers. Events are dropped if the subscriber doesn't keep
+// up with reading them. The returned function cancels the subscription and
+// closes the channel.
+func (sd *StreamDeck) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBufferSize)
+
+	sd.Lock()
+	if sd.subscribers == nil {
+		sd.subscribers = make(map[chan Event]struct{})
+	}
+	sd.subscribers[ch] = struct{}{}
+	sd.Unlock()
+
+	cancel := func() {
+		sd.Lock()
+		defer sd.Unlock()
+		if _, ok := sd.subscribers[ch]; ok {
+			delete(sd.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, cancel
+}
+
+// publish sends an event to all subscribers without blocking. The lock
+// must be held by the caller.
+func (sd *StreamDeck) publish(ev Event) {
+	for ch := range sd.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			sd.log.Debugf("subscriber too slow, dropping event of button %d", ev.BtnIndex)
+		}
+	}
+}
+
+// WaitForPress blocks until the given button is pressed or the context is
+// done, in which case the error of the context is returned. It works on a
+// separate subscription, so the BtnEvent callback and other subscribers
+// still receive the event. Serve must be running to receive button events.
+func (sd *StreamDeck) WaitForPress(ctx context.Context, btnIndex int) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	_, err := sd.waitForPress(ctx, func(i int) bool { return i == btnIndex })
+	return err
+}
+
+// WaitForAnyPress blocks until any button is pressed and returns its index,
+// or until the context is done, in which case the error of the context is
+// returned. Like WaitForPress it doesn't consume the event.
+func (sd *StreamDeck) WaitForAnyPress(ctx context.Context) (int, error) {
+	return sd.waitForPress(ctx, func(int) bool { return true })
+}
+
+// waitForPress waits for the press of a button accepted by match.
+func (sd *StreamDeck) waitForPress(ctx context.Context, match func(btnIndex int) bool) (int, error) {
+	events, cancel := sd.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case ev := <-events:
+			if ev.State == BtnPressed && match(ev.BtnIndex) {
+				return ev.BtnIndex, nil
+			}
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+}
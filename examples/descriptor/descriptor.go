@@ -0,0 +1,17 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+)
+
+// This example prints the USB descriptors of all connected Elgato devices.
+// Please attach its output when reporting a model which isn't detected.
+
+func main() {
+	if err := sdeck.DumpDescriptor(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
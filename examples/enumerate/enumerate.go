@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+)
+
+// This example lists all connected Stream Decks. The serial number can be
+// passed to NewStreamDeck to select a particular deck. Decks without a
+// unique serial number are listed with an ID derived from their USB path.
+
+func main() {
+	decks, err := sdeck.EnumerateDecks()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Found %d Elgato Stream Deck(s):\n", len(decks))
+	for _, deck := range decks {
+		fmt.Printf("\tSerialNumber:        %s\n", deck.Serial)
+		fmt.Printf("\tModel:               %s\n", deck.Model.Name)
+		fmt.Printf("\tUSB Path:            %s\n", deck.USBPath)
+		fmt.Printf("\tID:                  %s\n", deck.ID)
+	}
+}
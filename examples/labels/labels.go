@@ -35,7 +35,7 @@ func main() {
 	handleBtnEvents := func(btnIndex int, state sdeck.BtnState) {
 		fmt.Printf("Button: %d, %s\n", btnIndex, state)
 		if state == sdeck.BtnPressed {
-			col := color.RGBA{0, 0, 153, 0}
+			col := color.RGBA{0, 0, 153, 255}
 			labels[btnIndex].SetBgColor(image.NewUniform(col))
 		} else { // must be BtnReleased
 			col := color.RGBA{0, 0, 0, 255}
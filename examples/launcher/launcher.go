@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"log"
+	"os/exec"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/actionbutton"
+	"github.com/AKovalevich/streamdeck/label"
+	"github.com/gobuffalo/packr/v2"
+)
+
+// This example implements a paginated application launcher. Each app is
+// represented by an icon; pressing it launches the configured command. If
+// the command can not be started, the button is tinted red. The buttons in
+// the bottom row corners navigate to the previous / next page.
+
+type app struct {
+	icon    string
+	command []string
+}
+
+var apps = []app{
+	{"tux.png", []string{"xterm"}},
+	{"user.png", []string{"xdg-open", "."}},
+	{"octocat.jpg", []string{"xdg-open", "https://github.com"}},
+	{"doctor.png", []string{"htop"}},
+	{"warning.png", []string{"does-not-exist"}},
+	{"dices.png", []string{"xdg-open", "https://www.random.org"}},
+	{"lightbulb_on.png", []string{"xset", "dpms", "force", "on"}},
+	{"lightbulb_off.png", []string{"xset", "dpms", "force", "off"}},
+}
+
+// buttons used for navigation
+const (
+	prevBtn = 14
+	nextBtn = 10
+)
+
+var imgBox = packr.New("launcher-images", "../assets/images")
+
+func main() {
+	sd, err := sdeck.NewStreamDeck(nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer sd.ClearAllBtns()
+
+	pm, err := sdeck.NewPageManager(sd, newLauncherPage(sd, nil, apps))
+	if err != nil {
+		log.Panic(err)
+	}
+
+	sd.SetBtnEventCb(pm.Handle)
+
+	stop := make(chan bool)
+	sd.Serve(stop)
+}
+
+type launcherPage struct {
+	sd     *sdeck.StreamDeck
+	parent sdeck.Page
+	btns   map[int]*actionbutton.ActionButton
+	prev   *label.Label
+	next   *label.Label
+	rest   []app
+}
+
+// newLauncherPage creates a page with as many apps as fit on the panel.
+// The remaining apps are shown on the next page.
+func newLauncherPage(sd *sdeck.StreamDeck, parent sdeck.Page, apps []app) sdeck.Page {
+	lp := &launcherPage{
+		sd:     sd,
+		parent: parent,
+		btns:   make(map[int]*actionbutton.ActionButton),
+	}
+
+	pos := 0
+	for i, a := range apps {
+		for pos == prevBtn || pos == nextBtn {
+			pos++
+		}
+		if pos >= sdeck.NumButtons {
+			lp.rest = apps[i:]
+			break
+		}
+
+		icon, err := loadIcon(a.icon)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		command := a.command
+		btn, err := actionbutton.NewActionButton(sd, pos,
+			actionbutton.Icon(icon),
+			actionbutton.Action(func() error {
+				cmd := exec.Command(command[0], command[1:]...)
+				if err := cmd.Start(); err != nil {
+					return err
+				}
+				go cmd.Wait()
+				return nil
+			}))
+		if err != nil {
+			log.Panic(err)
+		}
+		lp.btns[pos] = btn
+		pos++
+	}
+
+	if parent != nil {
+		prev, err := label.NewLabel(sd, prevBtn, label.Text("<"))
+		if err != nil {
+			log.Panic(err)
+		}
+		lp.prev = prev
+	}
+
+	if len(lp.rest) > 0 {
+		next, err := label.NewLabel(sd, nextBtn, label.Text(">"))
+		if err != nil {
+			log.Panic(err)
+		}
+		lp.next = next
+	}
+
+	return lp
+}
+
+func (lp *launcherPage) SetActive(active bool) {
+}
+
+func (lp *launcherPage) Set(btnIndex int, state sdeck.BtnState) sdeck.Page {
+	if btnIndex == prevBtn && lp.prev != nil {
+		if state == sdeck.BtnPressed {
+			return lp.parent
+		}
+		return nil
+	}
+
+	if btnIndex == nextBtn && lp.next != nil {
+		if state == sdeck.BtnPressed {
+			return newLauncherPage(lp.sd, lp, lp.rest)
+		}
+		return nil
+	}
+
+	if btn, ok := lp.btns[btnIndex]; ok {
+		btn.Change(state)
+	}
+
+	return nil
+}
+
+func (lp *launcherPage) Draw() {
+	for _, btn := range lp.btns {
+		btn.Draw()
+	}
+	if lp.prev != nil {
+		lp.prev.Draw()
+	}
+	if lp.next != nil {
+		lp.next.Draw()
+	}
+}
+
+func (lp *launcherPage) Parent() sdeck.Page {
+	return lp.parent
+}
+
+func loadIcon(name string) (image.Image, error) {
+	data, err := imgBox.Find(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewBuffer(data))
+	return img, err
+}
@@ -9,7 +9,8 @@ import (
 
 // This example shows how to use the 'streamdeck/LedButton‘. It will
 // enumerate all the buttons on the panel with their ID and with a green LED
-// which can be activated / deactivated with a button press.
+// which can be activated / deactivated with a button press. The yellow
+// LEDs are only lit while the button is held.
 
 func main() {
 	sd, err := sdeck.NewStreamDeck(nil)
@@ -35,7 +36,8 @@ func main() {
 	// Yellow Buttons
 	for i := 5; i < 10; i++ {
 		text := fmt.Sprintf("%03d", i)
-		btn, err := ledbutton.NewLedButton(sd, i, ledbutton.Text(text), ledbutton.LedColor(ledbutton.LEDYellow))
+		btn, err := ledbutton.NewLedButton(sd, i, ledbutton.Text(text), ledbutton.LedColor(ledbutton.LEDYellow),
+			ledbutton.Mode(ledbutton.Momentary))
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -56,9 +58,8 @@ func main() {
 
 	btnChangedCb := func(btnIndex int, state sdeck.BtnState) {
 		fmt.Printf("Button: %d, %s\n", btnIndex, state)
-		if state == sdeck.BtnPressed {
-			btn := btns[btnIndex]
-			btn.SetState(!btn.State())
+		if err := btns[btnIndex].ChangeErr(state); err != nil {
+			fmt.Println(err)
 		}
 	}
 	sd.SetBtnEventCb(btnChangedCb)
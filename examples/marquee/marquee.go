@@ -0,0 +1,87 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"time"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+	"github.com/gobuffalo/packr/v2"
+	"github.com/golang/freetype"
+)
+
+// This example scrolls a text across the middle row of the Stream Deck.
+// The text is drawn into a Framebuffer spanning the whole panel; on each
+// tick only the buttons whose content changed are sent to the device.
+
+const text = "Hello Stream Deck! +++ "
+
+func main() {
+	fontBox := packr.New("marquee-fonts", "../assets/fonts")
+
+	data, err := fontBox.Find("mplus-1m-regular.ttf")
+	if err != nil {
+		log.Fatal(err)
+	}
+	font, err := freetype.ParseFont(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sd, err := sdeck.NewStreamDeck(nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer sd.Close()
+
+	fb := sd.NewFramebuffer()
+	caps := sd.Capabilities()
+
+	// the text is rendered once into a strip, which is then moved across
+	// the framebuffer
+	const fontSize = 48
+	c := freetype.NewContext()
+	c.SetDPI(72)
+	c.SetFont(font)
+	c.SetFontSize(fontSize)
+	c.SetSrc(image.NewUniform(color.RGBA{255, 200, 0, 255}))
+
+	// one em per character is enough for any glyph of the mono font
+	width := int(c.PointToFixed(fontSize)>>6) * len(text)
+	strip := image.NewRGBA(image.Rect(0, 0, width, caps.KeySize))
+	draw.Draw(strip, strip.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+	c.SetClip(strip.Bounds())
+	c.SetDst(strip)
+	// the baseline leaves room for the descenders
+	pos, err := c.DrawString(text, freetype.Pt(0, (caps.KeySize+fontSize)/2-6))
+	if err != nil {
+		log.Fatal(err)
+	}
+	strip = strip.SubImage(image.Rect(0, 0, pos.X.Ceil(), caps.KeySize)).(*image.RGBA)
+
+	// the middle row of keys (the upper one of the two rows of the Mini)
+	middle, err := sd.KeyRect((caps.Rows - 1) / 2 * caps.Columns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	row := image.Rect(0, middle.Min.Y, caps.PanelWidth, middle.Max.Y)
+	offset := 0
+
+	ticker := time.NewTicker(40 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		// draw the strip twice, so that the text wraps around seamlessly
+		stripWidth := strip.Bounds().Dx()
+		for x := -offset; x < caps.PanelWidth; x += stripWidth {
+			r := image.Rect(x, row.Min.Y, x+stripWidth, row.Max.Y).Intersect(row)
+			draw.Draw(fb, r, strip, image.Pt(r.Min.X-x, 0), draw.Src)
+		}
+		if err := fb.Present(); err != nil {
+			log.Println(err)
+		}
+		offset = (offset + 4) % stripWidth
+	}
+}
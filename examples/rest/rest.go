@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/rest"
+)
+
+// This example serves the REST API on http://localhost:8080, e.g.
+//
+//	curl -X POST --data-binary @icon.png localhost:8080/keys/0/image
+//	curl -X POST -d '{"text": "Hello"}' localhost:8080/keys/1/text
+//	curl -X POST -d '{"brightness": 50}' localhost:8080/brightness
+//	curl -N localhost:8080/events
+
+func main() {
+	sd, err := sdeck.NewStreamDeck(nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer sd.ClearAllBtns()
+
+	srv, err := rest.NewServer(sd)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	go func() {
+		log.Println("stream deck rest api on http://localhost:8080")
+		log.Fatal(http.ListenAndServe(":8080", srv))
+	}()
+
+	stop := make(chan bool)
+	sd.Serve(stop)
+}
@@ -29,7 +29,7 @@ func main() {
 
 	lineLabel := sdeck.TextLine{
 		Font:      monoFont,
-		FontColor: color.RGBA{255, 255, 0, 0}, // Yellow
+		FontColor: color.RGBA{255, 255, 0, 255}, // Yellow
 		FontSize:  22,
 		PosX:      10,
 		PosY:      5,
@@ -38,7 +38,7 @@ func main() {
 
 	linePressed := sdeck.TextLine{
 		Font:      monoFont,
-		FontColor: color.RGBA{255, 255, 255, 0}, // White
+		FontColor: color.RGBA{255, 255, 255, 255}, // White
 		FontSize:  14,
 		PosX:      12,
 		PosY:      30,
@@ -47,7 +47,7 @@ func main() {
 
 	lineReleased := sdeck.TextLine{
 		Font:      monoFont,
-		FontColor: color.RGBA{255, 0, 0, 0}, // Red
+		FontColor: color.RGBA{255, 0, 0, 255}, // Red
 		FontSize:  14,
 		PosX:      9,
 		PosY:      30,
@@ -55,12 +55,12 @@ func main() {
 	}
 
 	pressedText := sdeck.TextButton{
-		BgColor: color.RGBA{0, 0, 0, 0},
+		BgColor: color.RGBA{0, 0, 0, 255},
 		Lines:   []sdeck.TextLine{lineLabel, linePressed},
 	}
 
 	releasedText := sdeck.TextButton{
-		BgColor: color.RGBA{0, 0, 0, 0},
+		BgColor: color.RGBA{0, 0, 0, 255},
 		Lines:   []sdeck.TextLine{lineLabel, lineReleased},
 	}
 
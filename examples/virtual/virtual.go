@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/label"
+)
+
+// This example drives a virtual Stream Deck instead of real hardware. The
+// content of the panel can be watched in the browser at http://localhost:8080.
+// Clicking on a button of the preview simulates a button press.
+
+const page = `<!DOCTYPE html>
+<html>
+<head><title>Virtual Stream Deck</title></head>
+<body style="background:#222">
+<img id="panel" src="/panel.png" usemap="#buttons">
+<map name="buttons">%s</map>
+<script>
+setInterval(function() {
+	document.getElementById("panel").src = "/panel.png?" + Date.now();
+}, 250);
+</script>
+</body>
+</html>`
+
+func main() {
+	vd := sdeck.NewVirtualDevice()
+
+	sd, err := sdeck.NewStreamDeckWithDevice(nil, vd)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer sd.Close()
+
+	caps := sd.Capabilities()
+	labels := make(map[int]*label.Label)
+	for i := 0; i < caps.NumKeys; i++ {
+		l, err := label.NewLabel(sd, i, label.Text(strconv.Itoa(i)))
+		if err != nil {
+			log.Panic(err)
+		}
+		l.Draw()
+		labels[i] = l
+	}
+
+	sd.SetBtnEventCb(func(btnIndex int, state sdeck.BtnState) {
+		fmt.Printf("Button: %d, %s\n", btnIndex, state)
+		labels[btnIndex].Change(state)
+		labels[btnIndex].Draw()
+	})
+
+	// html image map with one clickable area per button
+	areas := ""
+	for i := 0; i < caps.NumKeys; i++ {
+		r, err := sd.KeyRect(i)
+		if err != nil {
+			log.Panic(err)
+		}
+		areas += fmt.Sprintf(`<area shape="rect" coords="%d,%d,%d,%d" href="/press?btn=%d">`,
+			r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, i)
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, page, areas)
+	})
+
+	http.HandleFunc("/panel.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		if err := vd.WritePNG(w); err != nil {
+			log.Println(err)
+		}
+	})
+
+	http.HandleFunc("/press", func(w http.ResponseWriter, r *http.Request) {
+		btnIndex, err := strconv.Atoi(r.URL.Query().Get("btn"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := vd.Press(btnIndex); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := vd.Release(btnIndex); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+
+	go func() {
+		log.Println("virtual stream deck preview on http://localhost:8080")
+		log.Fatal(http.ListenAndServe(":8080", nil))
+	}()
+
+	stop := make(chan bool)
+	sd.Serve(stop)
+}
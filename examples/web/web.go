@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	sdeck "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/web"
+)
+
+// This example serves a minimal browser UI at http://localhost:8080 to
+// monitor and control the Stream Deck through a WebSocket.
+
+const client = `<!DOCTYPE html>
+<html>
+<head><title>Stream Deck</title></head>
+<body>
+<p>
+	Button <input id="btn" type="number" min="0" max="14" value="0">
+	Text <input id="text" maxlength="5">
+	<button onclick="sendText()">Set Text</button>
+	Image <input id="image" type="file" accept="image/*" onchange="sendImage()">
+</p>
+<p>
+	Brightness <input id="brightness" type="range" min="0" max="100" value="70" onchange="sendBrightness()">
+</p>
+<pre id="log"></pre>
+<script>
+var ws = new WebSocket("ws://" + location.host + "/ws");
+ws.onmessage = function(e) {
+	document.getElementById("log").textContent = e.data + "\n" + document.getElementById("log").textContent;
+};
+function btn() {
+	return parseInt(document.getElementById("btn").value);
+}
+function sendText() {
+	ws.send(JSON.stringify({cmd: "text", btn: btn(), text: document.getElementById("text").value}));
+}
+function sendBrightness() {
+	ws.send(JSON.stringify({cmd: "brightness", brightness: parseInt(document.getElementById("brightness").value)}));
+}
+function sendImage() {
+	var reader = new FileReader();
+	reader.onload = function() {
+		var data = reader.result.substring(reader.result.indexOf(",") + 1);
+		ws.send(JSON.stringify({cmd: "image", btn: btn(), image: data}));
+	};
+	reader.readAsDataURL(document.getElementById("image").files[0]);
+}
+</script>
+</body>
+</html>`
+
+func main() {
+	sd, err := sdeck.NewStreamDeck(nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer sd.ClearAllBtns()
+
+	srv, err := web.NewServer(sd)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	http.Handle("/ws", srv)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, client)
+	})
+
+	go func() {
+		log.Println("stream deck web ui on http://localhost:8080")
+		log.Fatal(http.ListenAndServe(":8080", nil))
+	}()
+
+	stop := make(chan bool)
+	sd.Serve(stop)
+}
@@ -0,0 +1,124 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/draw"
+	"time"
+)
+
+// fadeFrameInterval is the time between two frames of a fade.
+const fadeFrameInterval = 40 * time.Millisecond
+
+// Easing maps the progress t (0 to 1) of a transition onto the progress of
+// the animated value, which should be 0 for t = 0 and 1 for t = 1. Easing
+// functions make transitions look natural.
+type Easing func(t float64) float64
+
+// EaseLinear progresses at a constant rate.
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseIn starts slowly and accelerates.
+func EaseIn(t float64) float64 {
+	return t * t
+}
+
+// EaseOut starts quickly and decelerates.
+func EaseOut(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOut accelerates in the first half and decelerates in the second
+// half of the transition.
+func EaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// FadeToImage fades the content of a button into img over the given
+// duration. The blend factor of every frame is taken from the easing
+// function; if easing is nil, EaseLinear is used. The image is scaled like
+// in FillImage. Like a Slideshow, the fade runs in the background and is
+// stopped by the returned stop function, by other content written to the
+// button or when the StreamDeck is closed.
+func (sd *StreamDeck) FadeToImage(btnIndex int, img image.Image, duration time.Duration, easing Easing) (stop func()) {
+	if sd.checkValidKeyIndex(btnIndex) != nil {
+		return func() {}
+	}
+	if easing == nil {
+		easing = EaseLinear
+	}
+
+	sd.Lock()
+	scaleMode := sd.scaleMode
+	sd.Unlock()
+
+	rect := img.Bounds()
+	if rect.Dx() != sd.model.keySize || rect.Dy() != sd.model.keySize {
+		img = sd.scale(img, sd.model.keySize, sd.model.keySize, scaleMode)
+	}
+
+	// the fade starts at the content currently displayed, or at black if
+	// it is unknown
+	sd.writeMu.Lock()
+	from := sd.cachedImage(btnIndex)
+	sd.writeMu.Unlock()
+	if from == nil {
+		from = image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
+		draw.Draw(from, from.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+	}
+
+	a := sd.startAnimation(btnIndex)
+	stop = func() {
+		sd.writeMu.Lock()
+		defer sd.writeMu.Unlock()
+		if sd.animations[btnIndex] == a {
+			sd.stopAnimation(btnIndex)
+		}
+	}
+
+	if duration <= 0 {
+		if err := sd.writeAnimationFrame(btnIndex, a, img); err != nil && err != errAnimationStopped {
+			sd.log.Warn(err.Error())
+		}
+		stop()
+		return stop
+	}
+
+	go func() {
+		defer stop()
+
+		ticker := time.NewTicker(fadeFrameInterval)
+		defer ticker.Stop()
+
+		start := time.Now()
+		for {
+			t := float64(time.Since(start)) / float64(duration)
+			if t > 1 {
+				t = 1
+			}
+			frame := BlendImages(from, img, easing(t))
+			err := sd.writeAnimationFrame(btnIndex, a, frame)
+			if err == errAnimationStopped {
+				return
+			}
+			if err != nil {
+				sd.log.Warn(err.Error())
+			}
+			if t == 1 {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+
+	return stop
+}
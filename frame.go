@@ -0,0 +1,105 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// Frame is an off-device buffer holding the content of all buttons. The
+// buttons of a Frame can be drawn independently from the Stream Deck and
+// swapped in at once with Present. This allows double-buffered updates of
+// the whole panel.
+type Frame struct {
+	sd   *StreamDeck
+	btns []*image.RGBA
+}
+
+// NewFrame returns a new Frame. The buttons of the frame are initialized
+// with the content currently displayed on the Stream Deck (or black if
+// unknown).
+func (sd *StreamDeck) NewFrame() *Frame {
+	f := &Frame{
+		sd:   sd,
+		btns: make([]*image.RGBA, sd.model.NumButtons),
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	for i := range f.btns {
+		img := sd.cachedImage(i)
+		if img == nil {
+			img = image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
+			draw.Draw(img, img.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+		}
+		f.btns[i] = img
+	}
+
+	return f
+}
+
+// Btn returns the image of a button within the Frame. It can be drawn on
+// directly; the changes become visible with the next call to Present.
+func (f *Frame) Btn(btnIndex int) (*image.RGBA, error) {
+	if btnIndex < 0 || btnIndex >= len(f.btns) {
+		return nil, fmt.Errorf("invalid key index")
+	}
+	return f.btns[btnIndex], nil
+}
+
+// SetImage replaces the image of a button within the Frame. The image is
+// scaled to the button size if necessary.
+func (f *Frame) SetImage(btnIndex int, img image.Image) error {
+	if btnIndex < 0 || btnIndex >= len(f.btns) {
+		return fmt.Errorf("invalid key index")
+	}
+
+	f.sd.Lock()
+	scaleMode := f.sd.scaleMode
+	f.sd.Unlock()
+
+	rect := img.Bounds()
+	if rect.Dx() != f.sd.model.keySize || rect.Dy() != f.sd.model.keySize {
+		img = f.sd.scale(img, f.sd.model.keySize, f.sd.model.keySize, scaleMode)
+	}
+	f.btns[btnIndex] = copyRGBA(img)
+	return nil
+}
+
+// Present sends the Frame to the Stream Deck. Only the buttons which differ
+// from the content currently displayed are written. All writes are
+// executed in one batch without interruption by other writes.
+func (f *Frame) Present() error {
+	btns := make([]image.Image, len(f.btns))
+	for i, btn := range f.btns {
+		btns[i] = btn
+	}
+	return f.sd.present(btns)
+}
+
+// present writes the images of all buttons in one batch. Only the buttons
+// which differ from the content currently displayed are written.
+func (sd *StreamDeck) present(btns []image.Image) error {
+	imgs := make([]*image.RGBA, len(btns))
+	bufs := make([][]byte, len(btns))
+	for i, btn := range btns {
+		imgs[i], bufs[i] = sd.encodeBtnImage(i, btn)
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	for i := range bufs {
+		// the batch takes over all buttons
+		sd.stopAnimation(i)
+		if sd.isCached(i, bufs[i]) {
+			continue
+		}
+		if err := sd.writeBtnBuf(i, imgs[i], bufs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Framebuffer treats the Stream Deck as one logical display. It is a
+// persistent canvas with the size of the panel (including the spacers, see
+// PanelGapIncluded) which can be drawn on incrementally, e.g. to scroll text
+// across several buttons. Present sends the buttons whose content changed.
+// Unlike FillPanel, the canvas is kept between the updates.
+type Framebuffer struct {
+	*image.RGBA
+	sd *StreamDeck
+}
+
+// NewFramebuffer returns a new Framebuffer. The canvas is initialized with
+// the content currently displayed on the buttons (or black if unknown).
+func (sd *StreamDeck) NewFramebuffer() *Framebuffer {
+	fb := &Framebuffer{
+		RGBA: image.NewRGBA(sd.model.panelRect()),
+		sd:   sd,
+	}
+	draw.Draw(fb.RGBA, fb.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	for i := 0; i < sd.model.NumButtons; i++ {
+		if img := sd.cachedImage(i); img != nil {
+			draw.Draw(fb.RGBA, sd.model.btnRect(i), img, image.Point{0, 0}, draw.Src)
+		}
+	}
+
+	return fb
+}
+
+// Present sends the canvas to the Stream Deck. Only the buttons whose
+// content differs from what is currently displayed are written. The
+// parts of the canvas located behind the spacers are not shown.
+func (fb *Framebuffer) Present() error {
+	btns := make([]image.Image, fb.sd.model.NumButtons)
+	for i := range btns {
+		btns[i] = fb.SubImage(fb.sd.model.btnRect(i))
+	}
+	return fb.sd.present(btns)
+}
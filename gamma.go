@@ -0,0 +1,54 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"math"
+)
+
+// gammaLUT maps the 8 bit channel values of an image onto the values sent
+// to the Stream Deck.
+type gammaLUT [256]byte
+
+// newGammaLUT returns the lookup table for the correction of a display with
+// the given gamma, relative to the gamma the images have been prepared for.
+func newGammaLUT(gamma float64) *gammaLUT {
+	var lut gammaLUT
+	for i := range lut {
+		v := math.Pow(float64(i)/255, 1/gamma)
+		lut[i] = byte(math.Round(v * 255))
+	}
+	return &lut
+}
+
+// apply corrects the encoded pixels of a button in place.
+func (lut *gammaLUT) apply(buf []byte) {
+	if lut == nil {
+		return
+	}
+	for i, v := range buf {
+		buf[i] = lut[v]
+	}
+}
+
+// SetDisplayGamma enables a gamma correction of the images written to the
+// buttons. The LCDs of the keys have a nonlinear response, which makes
+// images look darker than on a monitor. With a gamma above 1 the mid tones
+// are brightened, e.g. 1.8 works well for photos on the original Stream
+// Deck; black and white are not changed. A gamma of 1 disables the
+// correction, which is the default. The correction only applies to images
+// written afterwards and not to raw pixels sent with SetKeyImageRaw.
+func (sd *StreamDeck) SetDisplayGamma(gamma float64) error {
+	if gamma <= 0 || math.IsNaN(gamma) || math.IsInf(gamma, 0) {
+		return fmt.Errorf("invalid display gamma %v; must be positive", gamma)
+	}
+
+	var lut *gammaLUT
+	if gamma != 1 {
+		lut = newGammaLUT(gamma)
+	}
+
+	sd.Lock()
+	defer sd.Unlock()
+	sd.gamma = lut
+	return nil
+}
@@ -0,0 +1,62 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetResetGesture configures a hidden gesture: holding the given button for
+// at least hold executes action, e.g. to return a kiosk to its start page.
+// The action is executed once per hold, while the button is still held.
+// There is no visual feedback. Only one reset gesture can be configured;
+// calling SetResetGesture again replaces it, a nil action removes it.
+func (sd *StreamDeck) SetResetGesture(btnIndex int, hold time.Duration, action func()) error {
+	if action != nil {
+		if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+			return err
+		}
+		if hold <= 0 {
+			return fmt.Errorf("hold duration must be positive")
+		}
+	}
+
+	sd.Lock()
+	cancel := sd.resetGestureCancel
+	sd.resetGestureCancel = nil
+	sd.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	if action == nil {
+		return nil
+	}
+
+	events, cancel := sd.Subscribe()
+	sd.Lock()
+	sd.resetGestureCancel = cancel
+	sd.Unlock()
+
+	go func() {
+		var timer *time.Timer
+		for ev := range events {
+			if ev.BtnIndex != btnIndex {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+				timer = nil
+			}
+			if ev.State == BtnPressed {
+				timer = time.AfterFunc(hold, action)
+			}
+		}
+		// the subscription has been cancelled
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	return nil
+}
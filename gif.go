@@ -0,0 +1,141 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"time"
+)
+
+// gifDefaultDelay is the delay of GIF frames which don't specify one, like
+// most browsers do.
+const gifDefaultDelay = 100 * time.Millisecond
+
+// PlayGIF plays an animated GIF file on a button. All frames are decoded
+// and composited according to their disposal methods in advance, then they
+// are shown with their delays in a goroutine. If loop is true, the
+// animation is repeated until the returned stop function is called, other
+// content is written to the button or the StreamDeck is closed; otherwise
+// the last frame remains on the button and the animation ends. Frames are scaled to the button size
+// like with FillImage.
+func (sd *StreamDeck) PlayGIF(btnIndex int, path string, loop bool) (stop func(), err error) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode gif %s: %v", path, err)
+	}
+	if len(g.Image) == 0 {
+		return nil, fmt.Errorf("gif %s contains no frames", path)
+	}
+
+	frames := composeGIF(g)
+	delays := make([]time.Duration, len(frames))
+	for i := range delays {
+		delays[i] = gifDefaultDelay
+		if i < len(g.Delay) && g.Delay[i] > 0 {
+			delays[i] = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+	}
+
+	a := sd.startAnimation(btnIndex)
+	stop = func() {
+		sd.writeMu.Lock()
+		defer sd.writeMu.Unlock()
+		if sd.animations[btnIndex] == a {
+			sd.stopAnimation(btnIndex)
+		}
+	}
+
+	if err := sd.writeAnimationFrame(btnIndex, a, frames[0]); err != nil {
+		stop()
+		return nil, err
+	}
+
+	if len(frames) == 1 {
+		stop()
+		return stop, nil
+	}
+
+	go func() {
+		timer := time.NewTimer(delays[0])
+		defer timer.Stop()
+
+		pos := 0
+		for {
+			select {
+			case <-timer.C:
+				pos++
+				if pos == len(frames) {
+					if !loop {
+						stop()
+						return
+					}
+					pos = 0
+				}
+				err := sd.writeAnimationFrame(btnIndex, a, frames[pos])
+				if err == errAnimationStopped {
+					return
+				}
+				if err != nil {
+					sd.log.Warn(err.Error())
+				}
+				timer.Reset(delays[pos])
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+
+	return stop, nil
+}
+
+// composeGIF returns the frames of a GIF as they are displayed, i.e. every
+// (partial) frame drawn over the canvas left behind by its predecessors
+// according to their disposal methods.
+func composeGIF(g *gif.GIF) []*image.RGBA {
+	rect := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if rect.Empty() {
+		// the logical screen size is optional in practice; the screen
+		// starts at the origin and has to contain all frames
+		for _, frame := range g.Image {
+			max := frame.Bounds().Max
+			rect = rect.Union(image.Rect(0, 0, max.X, max.Y))
+		}
+	}
+
+	canvas := image.NewRGBA(rect)
+	frames := make([]*image.RGBA, 0, len(g.Image))
+	for i, frame := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = copyRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames = append(frames, copyRGBA(canvas))
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{0, 0}, draw.Src)
+		case gif.DisposalPrevious:
+			draw.Draw(canvas, canvas.Bounds(), previous, image.Point{0, 0}, draw.Src)
+		}
+	}
+	return frames
+}
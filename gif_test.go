@@ -0,0 +1,137 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// gifFrame returns a frame of the given bounds filled with c.
+func gifFrame(rect image.Rectangle, c color.Color) *image.Paletted {
+	frame := image.NewPaletted(rect, palette.Plan9)
+	idx := uint8(frame.Palette.Index(c))
+	for i := range frame.Pix {
+		frame.Pix[i] = idx
+	}
+	return frame
+}
+
+func TestComposeGIFDisposal(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+	transparent := color.RGBA{}
+
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			gifFrame(image.Rect(0, 0, 4, 4), red),
+			gifFrame(image.Rect(0, 0, 2, 2), blue),
+			gifFrame(image.Rect(3, 3, 4, 4), green),
+			gifFrame(image.Rect(2, 2, 3, 3), white),
+		},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone},
+		Config:   image.Config{Width: 4, Height: 4},
+	}
+
+	frames := composeGIF(g)
+	if len(frames) != 4 {
+		t.Fatalf("got %d frames, want 4", len(frames))
+	}
+
+	tests := []struct {
+		frame int
+		x, y  int
+		want  color.RGBA
+	}{
+		{0, 0, 0, red},
+		{1, 0, 0, blue},
+		{1, 3, 3, red},
+		// the background disposal of frame 1 clears its area
+		{2, 0, 0, transparent},
+		{2, 3, 3, green},
+		// the previous disposal of frame 2 restores the canvas before it
+		{3, 3, 3, red},
+		{3, 0, 0, transparent},
+		{3, 2, 2, white},
+	}
+	for _, tt := range tests {
+		if got := frames[tt.frame].RGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("frame %d at (%d, %d) = %v, want %v", tt.frame, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestComposeGIFWithoutScreenSize(t *testing.T) {
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			gifFrame(image.Rect(1, 1, 3, 3), color.RGBA{255, 0, 0, 255}),
+			gifFrame(image.Rect(2, 2, 4, 5), color.RGBA{0, 0, 255, 255}),
+		},
+	}
+
+	frames := composeGIF(g)
+	want := image.Rect(0, 0, 4, 5)
+	for i, frame := range frames {
+		if frame.Bounds() != want {
+			t.Errorf("bounds of frame %d = %v, want %v", i, frame.Bounds(), want)
+		}
+	}
+	// the frames keep their offsets within the logical screen
+	if got := frames[0].RGBAAt(0, 0); got != (color.RGBA{}) {
+		t.Errorf("frame 0 at (0, 0) = %v, want transparent", got)
+	}
+	if got := frames[0].RGBAAt(1, 1); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("frame 0 at (1, 1) = %v, want red", got)
+	}
+}
+
+func TestPlayGIFWithoutLoop(t *testing.T) {
+	sd, vd := newTestDeck(t, ProductID)
+
+	rect := image.Rect(0, 0, ButtonSize, ButtonSize)
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			gifFrame(rect, color.RGBA{255, 0, 0, 255}),
+			gifFrame(rect, color.RGBA{0, 0, 255, 255}),
+		},
+		Delay: []int{1, 1},
+	}
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.EncodeAll(f, g); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := sd.PlayGIF(2, path, false); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sd.writeMu.Lock()
+		_, running := sd.animations[2]
+		sd.writeMu.Unlock()
+		if !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("animation still registered after the last frame")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	want := color.RGBA{0, 0, 255, 255}
+	if got := keyCenter(sd, vd, 2); got != want {
+		t.Errorf("key shows %v after the animation, want the last frame %v", got, want)
+	}
+}
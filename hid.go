@@ -0,0 +1,283 @@
+//go:build hid
+// +build hid
+
+package StreamDeck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/karalabe/hid"
+)
+
+// When the package is built with the "hid" build tag, NewStreamDeck uses
+// the HID backend instead of raw libusb access.
+func init() {
+	newDefaultDevice = func(productID, vendorID uint16) Device {
+		return NewHIDDevice(productID, vendorID)
+	}
+	newDeckDevice = func(deck DeckInfo) Device {
+		return NewHIDDeviceWithSerial(deck.Model.ProductID, VendorID, deck.Serial)
+	}
+}
+
+// HIDDevice is an alternative to USBDevice which accesses the Stream Deck
+// through the HID stack of the operating system (hidapi) instead of libusb.
+// On macOS the kernel HID driver claims the Stream Deck, which makes raw
+// libusb access unreliable; the HIDDevice works there without detaching the
+// driver. It is only available when the package is built with the "hid"
+// build tag. Pass it to NewStreamDeckWithDevice to select it at runtime.
+type HIDDevice struct {
+	sync.Mutex
+	device    *hid.Device
+	info      hid.DeviceInfo
+	connected bool
+	productID uint16
+	vendorID  uint16
+	serial    string
+	// reports receives the input reports read by the pump goroutine, which
+	// runs while the device is open. hidapi reads can't be aborted, so
+	// ReadContext waits for the pump instead of reading itself.
+	reports     chan hidReport
+	done        chan struct{}
+	readTimeout time.Duration
+}
+
+// hidReport is an input report (or the error of the read) passed on by
+// the pump goroutine of a HIDDevice.
+type hidReport struct {
+	data []byte
+	err  error
+}
+
+// hidReadBufferSize is the size of the buffer for input reports read by the
+// pump goroutine. It exceeds the input reports of all supported models.
+const hidReadBufferSize = 1024
+
+// NewHIDDevice returns a HIDDevice for the first Stream Deck with the given
+// product and vendor ID.
+func NewHIDDevice(productID, vendorID uint16) *HIDDevice {
+	return &HIDDevice{
+		productID: productID,
+		vendorID:  vendorID,
+	}
+}
+
+// NewHIDDeviceWithSerial returns a HIDDevice for the Stream Deck with the
+// given product and vendor ID and serial number.
+func NewHIDDeviceWithSerial(productID, vendorID uint16, serial string) *HIDDevice {
+	return &HIDDevice{
+		productID: productID,
+		vendorID:  vendorID,
+		serial:    serial,
+	}
+}
+
+func (hidDevice *HIDDevice) Connect() error {
+	if !hid.Supported() {
+		return errors.New("hid backend not supported on this platform")
+	}
+
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+
+	for _, info := range hid.Enumerate(hidDevice.vendorID, hidDevice.productID) {
+		if hidDevice.serial != "" && info.Serial != hidDevice.serial {
+			continue
+		}
+		device, err := info.Open()
+		if err != nil {
+			return err
+		}
+		hidDevice.device = device
+		hidDevice.info = info
+		hidDevice.connected = true
+		hidDevice.reports = make(chan hidReport)
+		hidDevice.done = make(chan struct{})
+		go hidDevice.pump(device, hidDevice.reports, hidDevice.done)
+		return nil
+	}
+
+	if hidDevice.serial != "" {
+		return errors.New("no hid device found with serial number " + hidDevice.serial)
+	}
+	return errors.New("no one devices")
+}
+
+func (hidDevice *HIDDevice) Close() error {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+
+	if hidDevice.device == nil {
+		return nil
+	}
+	// closing the device terminates a pending read of the pump
+	close(hidDevice.done)
+	err := hidDevice.device.Close()
+	hidDevice.device = nil
+	hidDevice.connected = false
+	return err
+}
+
+// pump reads the input reports of device until a read fails or the device
+// is closed.
+func (hidDevice *HIDDevice) pump(device *hid.Device, reports chan<- hidReport, done <-chan struct{}) {
+	for {
+		buf := make([]byte, hidReadBufferSize)
+		n, err := device.Read(buf)
+		select {
+		case reports <- hidReport{data: buf[:n], err: err}:
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// SetReadTimeout sets the maximum duration Read waits for an input report.
+// If it expires, Read returns ErrReadTimeout. A timeout of zero blocks
+// until a report is received, which is the default.
+func (hidDevice *HIDDevice) SetReadTimeout(timeout time.Duration) {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	hidDevice.readTimeout = timeout
+}
+
+func (hidDevice *HIDDevice) IsConnected() bool {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	return hidDevice.connected
+}
+
+func (hidDevice *HIDDevice) SetConnected(connected bool) {
+	hidDevice.Lock()
+	hidDevice.connected = connected
+	hidDevice.Unlock()
+}
+
+func (hidDevice *HIDDevice) GetSerialNumber() (string, error) {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	if hidDevice.device == nil {
+		return "", errors.New("device not connected")
+	}
+	return hidDevice.info.Serial, nil
+}
+
+func (hidDevice *HIDDevice) GetProductID() uint16 {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	return hidDevice.productID
+}
+
+func (hidDevice *HIDDevice) GetVendorID() uint16 {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	return hidDevice.vendorID
+}
+
+// GetHIDPath returns the platform specific path of the HID device. The path
+// is empty until the device is connected.
+func (hidDevice *HIDDevice) GetHIDPath() string {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	return hidDevice.info.Path
+}
+
+func (hidDevice *HIDDevice) handle() (*hid.Device, error) {
+	hidDevice.Lock()
+	defer hidDevice.Unlock()
+	if !hidDevice.connected || hidDevice.device == nil {
+		return nil, errors.New("device not connected")
+	}
+	return hidDevice.device, nil
+}
+
+func (hidDevice *HIDDevice) Read(data []byte) (int, error) {
+	return hidDevice.ReadContext(context.Background(), data)
+}
+
+// ReadContext reads an input report like Read. It returns the error of ctx
+// as soon as ctx is done; the device remains connected in that case.
+func (hidDevice *HIDDevice) ReadContext(ctx context.Context, data []byte) (int, error) {
+	hidDevice.Lock()
+	connected := hidDevice.connected && hidDevice.device != nil
+	reports := hidDevice.reports
+	done := hidDevice.done
+	timeout := hidDevice.readTimeout
+	hidDevice.Unlock()
+
+	if !connected {
+		return 0, errors.New("device not connected")
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case report := <-reports:
+		if report.err != nil {
+			hidDevice.SetConnected(false)
+			return 0, report.err
+		}
+		return copy(data, report.data), nil
+	case <-done:
+		return 0, errors.New("device closed")
+	case <-expired:
+		return 0, ErrReadTimeout
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (hidDevice *HIDDevice) Write(data []byte) (int, error) {
+	device, err := hidDevice.handle()
+	if err != nil {
+		return 0, err
+	}
+	return device.Write(data)
+}
+
+// PrefixesReportID returns false on all platforms: output reports passed to
+// Write must start with the report ID. karalabe/hid hands the first byte to
+// hidapi as report number; on Windows it prepends the 0x00 report number
+// hidapi expects there itself, which doesn't replace the report ID of the
+// Stream Deck reports.
+func (hidDevice *HIDDevice) PrefixesReportID() bool {
+	return false
+}
+
+// GetFeatureReport reads a HID feature report from the device. The first
+// byte of data must contain the report ID; the report is read into data.
+func (hidDevice *HIDDevice) GetFeatureReport(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errors.New("feature report must contain at least the report ID")
+	}
+	device, err := hidDevice.handle()
+	if err != nil {
+		return 0, err
+	}
+	return device.GetFeatureReport(data)
+}
+
+// SendFeatureReport sends a HID feature report to the device. The first
+// byte of data must contain the report ID.
+func (hidDevice *HIDDevice) SendFeatureReport(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("feature report must contain at least the report ID")
+	}
+	device, err := hidDevice.handle()
+	if err != nil {
+		return err
+	}
+	_, err = device.SendFeatureReport(data)
+	return err
+}
@@ -0,0 +1,97 @@
+// Package hotkey binds the buttons of a Stream Deck to media keys of the
+// operating system, e.g. to control the music player. The keys are sent by
+// a Sender; the default Sender depends on the platform: it uses xdotool on
+// Linux (X11) and the Win32 API on Windows. Other platforms can provide
+// their own Sender.
+package hotkey
+
+import (
+	"fmt"
+
+	sd "github.com/AKovalevich/streamdeck"
+)
+
+// MediaKey is a media key of the keyboard.
+type MediaKey int
+
+const (
+	// PlayPause toggles between playing and pausing.
+	PlayPause MediaKey = iota
+	// Next skips to the next track.
+	Next
+	// Previous skips to the previous track.
+	Previous
+	// Stop stops playing.
+	Stop
+	// VolumeUp increases the volume.
+	VolumeUp
+	// VolumeDown decreases the volume.
+	VolumeDown
+	// Mute toggles muting.
+	Mute
+)
+
+func (k MediaKey) String() string {
+	switch k {
+	case PlayPause:
+		return "PlayPause"
+	case Next:
+		return "Next"
+	case Previous:
+		return "Previous"
+	case Stop:
+		return "Stop"
+	case VolumeUp:
+		return "VolumeUp"
+	case VolumeDown:
+		return "VolumeDown"
+	case Mute:
+		return "Mute"
+	}
+	return fmt.Sprintf("MediaKey(%d)", int(k))
+}
+
+// Sender sends key presses to the operating system.
+type Sender interface {
+	// SendMediaKey presses and releases a media key.
+	SendMediaKey(key MediaKey) error
+}
+
+// DefaultSender is the Sender of the platform used by BindMediaKey. It
+// returns an error for every key on platforms without support.
+var DefaultSender Sender = platformSender{}
+
+// BindMediaKey binds a button to a media key, which is sent with the
+// DefaultSender whenever the button is pressed. The binding uses the action
+// registry of the StreamDeck (see StreamDeck.BindKey), so it can be removed
+// with UnbindKey. Errors while sending are logged.
+func BindMediaKey(streamDeck *sd.StreamDeck, btnIndex int, key MediaKey) error {
+	return BindMediaKeyWithSender(streamDeck, btnIndex, key, DefaultSender)
+}
+
+// BindMediaKeyWithSender is like BindMediaKey, but sends the key with the
+// given Sender.
+func BindMediaKeyWithSender(streamDeck *sd.StreamDeck, btnIndex int, key MediaKey, sender Sender) error {
+	if streamDeck == nil {
+		return fmt.Errorf("stream deck must not be nil")
+	}
+	if sender == nil {
+		return fmt.Errorf("sender must not be nil")
+	}
+	if key < PlayPause || key > Mute {
+		return fmt.Errorf("unknown media key %v", key)
+	}
+
+	// each button gets its own action, so that buttons bound to the same
+	// key can use different senders
+	name := fmt.Sprintf("hotkey.%d.%v", btnIndex, key)
+	err := streamDeck.RegisterAction(name, func() {
+		if err := sender.SendMediaKey(key); err != nil {
+			streamDeck.Log().Warn(fmt.Sprintf("unable to send media key %v: %v", key, err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return streamDeck.BindKey(btnIndex, name)
+}
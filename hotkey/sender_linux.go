@@ -0,0 +1,33 @@
+package hotkey
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// xdotoolKeys are the X11 keysyms of the media keys.
+var xdotoolKeys = map[MediaKey]string{
+	PlayPause:  "XF86AudioPlay",
+	Next:       "XF86AudioNext",
+	Previous:   "XF86AudioPrev",
+	Stop:       "XF86AudioStop",
+	VolumeUp:   "XF86AudioRaiseVolume",
+	VolumeDown: "XF86AudioLowerVolume",
+	Mute:       "XF86AudioMute",
+}
+
+// platformSender sends the keys with xdotool, which has to be installed
+// and requires an X11 session (or XWayland).
+type platformSender struct{}
+
+func (platformSender) SendMediaKey(key MediaKey) error {
+	keysym, ok := xdotoolKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown media key %v", key)
+	}
+	out, err := exec.Command("xdotool", "key", keysym).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xdotool failed: %v: %s", err, out)
+	}
+	return nil
+}
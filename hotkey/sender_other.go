@@ -0,0 +1,16 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package hotkey
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// platformSender is used on platforms without support for media keys.
+type platformSender struct{}
+
+func (platformSender) SendMediaKey(key MediaKey) error {
+	return fmt.Errorf("sending media keys is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,40 @@
+package hotkey
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// virtual key codes of the media keys
+var virtualKeys = map[MediaKey]uintptr{
+	PlayPause:  0xB3, // VK_MEDIA_PLAY_PAUSE
+	Next:       0xB0, // VK_MEDIA_NEXT_TRACK
+	Previous:   0xB1, // VK_MEDIA_PREV_TRACK
+	Stop:       0xB2, // VK_MEDIA_STOP
+	VolumeUp:   0xAF, // VK_VOLUME_UP
+	VolumeDown: 0xAE, // VK_VOLUME_DOWN
+	Mute:       0xAD, // VK_VOLUME_MUTE
+}
+
+const (
+	keyeventfExtendedKey = 0x1
+	keyeventfKeyUp       = 0x2
+)
+
+var keybdEvent = syscall.NewLazyDLL("user32.dll").NewProc("keybd_event")
+
+// platformSender synthesizes the key presses with keybd_event.
+type platformSender struct{}
+
+func (platformSender) SendMediaKey(key MediaKey) error {
+	vk, ok := virtualKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown media key %v", key)
+	}
+	if err := keybdEvent.Find(); err != nil {
+		return err
+	}
+	keybdEvent.Call(vk, 0, keyeventfExtendedKey, 0)
+	keybdEvent.Call(vk, 0, keyeventfExtendedKey|keyeventfKeyUp, 0)
+	return nil
+}
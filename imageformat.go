@@ -0,0 +1,107 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// RawImageSize is the size (in bytes) of a raw button image of the original
+// Stream Deck as expected by SetKeyImageRaw. The other models expect raw
+// images of their key size (see rawImageSize).
+const RawImageSize = ButtonSize * ButtonSize * 3
+
+// rawImageSize returns the size (in bytes) of a raw key image of the model.
+func (sd *StreamDeck) rawImageSize() int {
+	return sd.model.keySize * sd.model.keySize * 3
+}
+
+// SetKeyImageRaw fills the given key with a raw image. The buffer must
+// contain KeySize x KeySize pixels (see Capabilities) with 3 bytes per
+// pixel in the order blue, green, red. The pixels are stored row by row,
+// starting at the top left corner.
+func (sd *StreamDeck) SetKeyImageRaw(btnIndex int, bgr []byte) error {
+	if size := sd.rawImageSize(); len(bgr) != size {
+		return fmt.Errorf("raw image must contain %d bytes, got %d", size, len(bgr))
+	}
+
+	size := sd.model.keySize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < size*size; i++ {
+		p := bgr[i*3 : i*3+3]
+		img.SetRGBA(i%size, i/size, color.RGBA{p[2], p[1], p[0], 255})
+	}
+
+	return sd.FillImage(btnIndex, img)
+}
+
+// FillImageFromFileFormat fills the given key with an image from a file,
+// like FillImageFromFile. Instead of detecting the format from the content,
+// the decoder for format is used. Supported formats are "png", "jpeg"
+// (or "jpg"), "gif" and "raw". A raw file contains exactly one button image
+// as expected by SetKeyImageRaw.
+func (sd *StreamDeck) FillImageFromFileFormat(btnIndex int, path, format string) error {
+	format = strings.ToLower(format)
+
+	var decode func(io.Reader) (image.Image, error)
+	switch format {
+	case "png":
+		decode = png.Decode
+	case "jpeg", "jpg":
+		decode = jpeg.Decode
+	case "gif":
+		decode = gif.Decode
+	case "raw":
+	default:
+		return fmt.Errorf("unsupported image format %q", format)
+	}
+
+	reader, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if format == "raw" {
+		info, err := reader.Stat()
+		if err != nil {
+			return err
+		}
+		if size := int64(sd.rawImageSize()); info.Size() != size {
+			return fmt.Errorf("raw image file %s must contain %d bytes, got %d",
+				path, size, info.Size())
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return sd.SetKeyImageRaw(btnIndex, data)
+	}
+
+	img, err := decode(reader)
+	if err != nil {
+		return fmt.Errorf("unable to decode %s as %s: %v", path, format, err)
+	}
+
+	return sd.FillImage(btnIndex, img)
+}
+
+// jpegQuality is the quality of the JPEG images sent to models which expect
+// JPEG compressed key images.
+const jpegQuality = 95
+
+// encodeJPEG encodes a key image as JPEG.
+func encodeJPEG(img image.Image) []byte {
+	var buf bytes.Buffer
+	// encoding into memory doesn't fail for valid images
+	jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	return buf.Bytes()
+}
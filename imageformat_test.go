@@ -0,0 +1,31 @@
+package StreamDeck
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSetKeyImageRawKeySize(t *testing.T) {
+	for _, m := range models {
+		t.Run(m.Name, func(t *testing.T) {
+			sd, vd := newTestDeck(t, m.ProductID)
+
+			if err := sd.SetKeyImageRaw(0, make([]byte, RawImageSize+3)); err == nil {
+				t.Error("SetKeyImageRaw accepted a raw image of the wrong size")
+			}
+
+			// blue, green, red
+			raw := make([]byte, m.keySize*m.keySize*3)
+			for i := 0; i < len(raw); i += 3 {
+				raw[i+2] = 255
+			}
+			if err := sd.SetKeyImageRaw(0, raw); err != nil {
+				t.Fatal(err)
+			}
+			want := color.RGBA{255, 0, 0, 255}
+			if got := keyCenter(sd, vd, 0); !colorNear(got, want) {
+				t.Errorf("key color = %v, want %v", got, want)
+			}
+		})
+	}
+}
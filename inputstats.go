@@ -0,0 +1,24 @@
+package StreamDeck
+
+// InputStats contains counters of the input processed by Serve, which help
+// to diagnose lagging or missing button events.
+type InputStats struct {
+	// Reports is the number of input reports read from the device.
+	Reports uint64
+	// Dispatched is the number of button events passed to the callbacks
+	// and subscribers.
+	Dispatched uint64
+	// Dropped is the number of button events discarded by the event
+	// throttle (see SetEventThrottle).
+	Dropped uint64
+}
+
+// InputStats returns the counters of the input processed since the
+// StreamDeck has been created. Comparing the number of reports over time
+// with the rate at which the device should report shows whether input is
+// lost before it reaches the library.
+func (sd *StreamDeck) InputStats() InputStats {
+	sd.Lock()
+	defer sd.Unlock()
+	return sd.inputStats
+}
@@ -0,0 +1,120 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"sync"
+)
+
+// KeyGroup links several adjacent buttons into one big button. An image
+// filled into the group is tiled across the member buttons and a press on
+// any member is reported as a press of the group.
+type KeyGroup struct {
+	sync.Mutex
+	sd      *StreamDeck
+	btns    map[int]bool
+	rect    image.Rectangle
+	onPress func()
+	cancel  func()
+}
+
+// NewKeyGroup creates a KeyGroup from the given buttons. The buttons must
+// form a rectangle on the panel, e.g. a 2x2 block.
+func (sd *StreamDeck) NewKeyGroup(btnIndices []int) (*KeyGroup, error) {
+	if len(btnIndices) == 0 {
+		return nil, fmt.Errorf("key group must contain at least one button")
+	}
+
+	btns := make(map[int]bool, len(btnIndices))
+	var rect image.Rectangle
+	for _, btnIndex := range btnIndices {
+		if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+			return nil, err
+		}
+		if btns[btnIndex] {
+			return nil, fmt.Errorf("button %d is contained several times in key group", btnIndex)
+		}
+		btns[btnIndex] = true
+		rect = rect.Union(sd.model.btnRect(btnIndex))
+	}
+
+	// the group is rectangular if it contains every button located within
+	// its bounding rectangle
+	for btnIndex := 0; btnIndex < sd.model.NumButtons; btnIndex++ {
+		if sd.model.btnRect(btnIndex).In(rect) && !btns[btnIndex] {
+			return nil, fmt.Errorf("key group is not rectangular, button %d is missing", btnIndex)
+		}
+	}
+
+	grp := &KeyGroup{
+		sd:   sd,
+		btns: btns,
+		rect: rect,
+	}
+
+	events, cancel := sd.Subscribe()
+	grp.cancel = cancel
+	go grp.handleEvents(events)
+
+	return grp, nil
+}
+
+// FillImage tiles an image across the buttons of the group. The image is
+// scaled to the size of the group including the spacers between its
+// buttons, according to the ScaleMode.
+func (grp *KeyGroup) FillImage(img image.Image) error {
+	return grp.sd.FillRegion(grp.rect, img)
+}
+
+// OnPress sets a callback which is executed when any button of the group
+// is pressed.
+func (grp *KeyGroup) OnPress(fn func()) {
+	grp.Lock()
+	defer grp.Unlock()
+	grp.onPress = fn
+}
+
+// Contains returns true if the button is a member of the group.
+func (grp *KeyGroup) Contains(btnIndex int) bool {
+	return grp.btns[btnIndex]
+}
+
+// Close stops routing the button events to the group.
+func (grp *KeyGroup) Close() {
+	grp.cancel()
+}
+
+func (grp *KeyGroup) handleEvents(events <-chan Event) {
+	for ev := range events {
+		if ev.State != BtnPressed || !grp.btns[ev.BtnIndex] {
+			continue
+		}
+		grp.Lock()
+		fn := grp.onPress
+		grp.Unlock()
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// FillRegion fills all buttons located within rect (in panel coordinates,
+// see PanelWidth and PanelHeight of the Capabilities) with an image. The
+// image is scaled to the size of rect according to the ScaleMode; the parts
+// behind the spacers are not shown.
+func (sd *StreamDeck) FillRegion(rect image.Rectangle, img image.Image) error {
+	if rect.Empty() || !rect.In(sd.model.panelRect()) {
+		return fmt.Errorf("region %v is not located within the panel", rect)
+	}
+
+	sd.Lock()
+	scaleMode := sd.scaleMode
+	sd.Unlock()
+
+	bounds := img.Bounds()
+	if bounds.Dx() != rect.Dx() || bounds.Dy() != rect.Dy() {
+		img = sd.scale(img, rect.Dx(), rect.Dy(), scaleMode)
+	}
+
+	return sd.render(rect, img)
+}
@@ -0,0 +1,63 @@
+package StreamDeck
+
+import (
+	"image"
+)
+
+// keyImages are the images of a button set with SetKeyImages.
+type keyImages struct {
+	normal  image.Image
+	pressed image.Image
+}
+
+// SetKeyImages shows normal on a button and automatically swaps it with
+// pressed while the button is held, like a physical button going down. The
+// swap is done by Serve before the callbacks of the event are executed. If
+// pressed is nil, the button keeps showing normal. A nil normal image
+// removes the configuration; the content of the button is left unchanged.
+// The images are handled like in FillImage. Errors are logged.
+func (sd *StreamDeck) SetKeyImages(btnIndex int, normal, pressed image.Image) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		sd.log.Warn(err.Error())
+		return
+	}
+
+	sd.Lock()
+	if normal == nil {
+		delete(sd.keyImages, btnIndex)
+		sd.Unlock()
+		return
+	}
+	if sd.keyImages == nil {
+		sd.keyImages = make(map[int]keyImages)
+	}
+	sd.keyImages[btnIndex] = keyImages{normal: normal, pressed: pressed}
+	img := normal
+	if pressed != nil && sd.btnState[btnIndex] == BtnPressed {
+		img = pressed
+	}
+	sd.Unlock()
+
+	if err := sd.FillImage(btnIndex, img); err != nil {
+		sd.log.Warn(err.Error())
+	}
+}
+
+// swapKeyImage shows the image set with SetKeyImages which corresponds to
+// the state of a button event. The lock must not be held by the caller.
+func (sd *StreamDeck) swapKeyImage(ev Event) {
+	sd.Lock()
+	images, ok := sd.keyImages[ev.BtnIndex]
+	sd.Unlock()
+	if !ok || images.pressed == nil {
+		return
+	}
+
+	img := images.normal
+	if ev.State == BtnPressed {
+		img = images.pressed
+	}
+	if err := sd.FillImage(ev.BtnIndex, img); err != nil {
+		sd.log.Warn(err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package label
+
+import (
+	"sync"
+	"time"
+
+	sd "github.com/AKovalevich/streamdeck"
+)
+
+// ClockLabel is a Label which shows the current time. It updates itself
+// every second until it is closed.
+type ClockLabel struct {
+	*Label
+	format string
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// NewClockLabel is the constructor of a ClockLabel. The time is formatted
+// according to format (see time.Format), e.g. "15:04". Like every Label
+// the formatted time must not exceed 5 characters.
+func NewClockLabel(sd *sd.StreamDeck, btnIndex int, format string, options ...func(*Label)) (*ClockLabel, error) {
+
+	l, err := NewLabel(sd, btnIndex, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := &ClockLabel{
+		Label:  l,
+		format: format,
+		stop:   make(chan struct{}),
+	}
+
+	if err := cl.update(); err != nil {
+		return nil, err
+	}
+
+	go cl.run()
+
+	return cl, nil
+}
+
+// Close stops updating the ClockLabel.
+func (cl *ClockLabel) Close() {
+	cl.once.Do(func() {
+		close(cl.stop)
+	})
+}
+
+func (cl *ClockLabel) run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := cl.update(); err != nil {
+				cl.streamDeck.Log().Warn(err.Error())
+			}
+		case <-cl.stop:
+			return
+		}
+	}
+}
+
+// update renders the current time, if it has changed since the last update.
+func (cl *ClockLabel) update() error {
+	text := time.Now().Format(cl.format)
+
+	cl.Lock()
+	changed := cl.text != text
+	cl.text = text
+	cl.Unlock()
+
+	if !changed {
+		return nil
+	}
+	return cl.Draw()
+}
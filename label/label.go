@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"image/draw"
 	"log"
+	"sync"
 
 	sd "github.com/AKovalevich/streamdeck"
 	"github.com/gobuffalo/packr/v2"
@@ -15,6 +16,7 @@ import (
 
 // Label is a basic Element for the StreamDeck.
 type Label struct {
+	sync.Mutex
 	streamDeck *sd.StreamDeck
 	text       string
 	id         int
@@ -53,7 +55,6 @@ func NewLabel(sd *sd.StreamDeck, btnIndex int, options ...func(*Label)) (*Label,
 		streamDeck: sd,
 		id:         btnIndex,
 		text:       "",
-		textColor:  image.White,
 		bgColor:    image.Black,
 	}
 
@@ -66,7 +67,7 @@ func NewLabel(sd *sd.StreamDeck, btnIndex int, options ...func(*Label)) (*Label,
 
 func (l *Label) Change(state sd.BtnState) {
 	if state == sd.BtnPressed {
-		col := color.RGBA{0, 0, 153, 0}
+		col := color.RGBA{0, 0, 153, 255}
 		l.SetBgColor(image.NewUniform(col))
 	} else { // must be BtnReleased
 		col := color.RGBA{0, 0, 0, 255}
@@ -77,9 +78,13 @@ func (l *Label) Change(state sd.BtnState) {
 	}
 }
 
-// Draw renders the Label on the designated Button.
+// Draw renders the Label on the designated Button. It is safe to call Draw
+// concurrently with the other methods of the Label.
 func (l *Label) Draw() error {
-	img := image.NewRGBA(image.Rect(0, 0, sd.ButtonSize, sd.ButtonSize))
+	l.Lock()
+	defer l.Unlock()
+	size := l.streamDeck.Capabilities().KeySize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
 	l.addBgColor(l.bgColor, img)
 	if err := l.addText(l.text, img); err != nil {
 		return err
@@ -89,11 +94,15 @@ func (l *Label) Draw() error {
 
 // SetText sets the text of the Label.
 func (l *Label) SetText(text string) {
+	l.Lock()
+	defer l.Unlock()
 	l.text = text
 }
 
 // SetBgColor sets the background color of the Label.
 func (l *Label) SetBgColor(color *image.Uniform) {
+	l.Lock()
+	defer l.Unlock()
 	l.bgColor = color
 }
 
@@ -143,6 +152,15 @@ var oneLine = textParams{
 	posY:     20,
 }
 
+// fontColor returns the text color of the Label. Unless a text color has
+// been set, the color contrasting best with the background is used.
+func (l *Label) fontColor() color.Color {
+	if l.textColor != nil {
+		return l.textColor
+	}
+	return sd.ContrastColor(l.bgColor)
+}
+
 func (l *Label) addText(text string, img *image.RGBA) error {
 
 	var p textParams
@@ -169,8 +187,11 @@ func (l *Label) addText(text string, img *image.RGBA) error {
 	c.SetFontSize(p.fontSize)
 	c.SetClip(img.Bounds())
 	c.SetDst(img)
-	c.SetSrc(image.NewUniform(l.textColor))
-	pt := freetype.Pt(p.posX, p.posY+int(c.PointToFixed(24)>>6))
+	c.SetSrc(image.NewUniform(l.fontColor()))
+	// the positions are given for the original key size; keep the text
+	// centered on larger keys
+	offset := (img.Bounds().Dx() - sd.ButtonSize) / 2
+	pt := freetype.Pt(offset+p.posX, offset+p.posY+int(c.PointToFixed(24)>>6))
 
 	if _, err := c.DrawString(text, pt); err != nil {
 		return err
@@ -0,0 +1,81 @@
+package label
+
+import (
+	"sync"
+	"time"
+
+	sd "github.com/AKovalevich/streamdeck"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// NumberLabel is a Label which shows a number formatted according to the
+// conventions of a language (e.g. with thousands separators). The value
+// can either be set directly or polled periodically from a source.
+type NumberLabel struct {
+	*Label
+	printer *message.Printer
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewNumberLabel is the constructor of a NumberLabel. The number is
+// formatted according to the conventions of lang. Like every Label the
+// formatted number must not exceed 5 characters.
+func NewNumberLabel(sd *sd.StreamDeck, btnIndex int, lang language.Tag, options ...func(*Label)) (*NumberLabel, error) {
+
+	l, err := NewLabel(sd, btnIndex, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	nl := &NumberLabel{
+		Label:   l,
+		printer: message.NewPrinter(lang),
+		stop:    make(chan struct{}),
+	}
+
+	return nl, nil
+}
+
+// SetValue sets the number of the label and renders it.
+func (nl *NumberLabel) SetValue(value int64) error {
+	text := nl.printer.Sprintf("%d", value)
+
+	nl.Lock()
+	changed := nl.text != text
+	nl.text = text
+	nl.Unlock()
+
+	if !changed {
+		return nil
+	}
+	return nl.Draw()
+}
+
+// Poll retrieves the value from source in the given interval and renders it
+// until the NumberLabel is closed.
+func (nl *NumberLabel) Poll(interval time.Duration, source func() int64) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := nl.SetValue(source()); err != nil {
+					nl.streamDeck.Log().Warn(err.Error())
+				}
+			case <-nl.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops polling the value of the NumberLabel.
+func (nl *NumberLabel) Close() {
+	nl.once.Do(func() {
+		close(nl.stop)
+	})
+}
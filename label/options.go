@@ -14,7 +14,8 @@ func Text(text string) func(*Label) {
 	}
 }
 
-// TextColor is a functional option which sets the text color.
+// TextColor is a functional option which sets the text color. By default
+// black or white is chosen, whichever contrasts best with the background.
 func TextColor(c color.Color) func(*Label) {
 	return func(l *Label) {
 		l.textColor = c
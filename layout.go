@@ -0,0 +1,167 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Layout describes the content of the buttons. It is the schema of the
+// JSON layout files read by LoadLayout and written by ExportLayout.
+type Layout struct {
+	Keys []LayoutKey `json:"keys"`
+}
+
+// LayoutKey describes the content of a single button. If Image is set, the
+// button shows the image, which is either a file path or a data URI (see
+// FillImageFromDataURI). Otherwise Text is written centered onto the button
+// (see FillText) and Color, a hex value like "#ff8800", fills the
+// background. Action binds the button to a registered action by its name
+// (see BindKey); a key with nothing but an Action leaves the content of the
+// button untouched.
+type LayoutKey struct {
+	Index  int    `json:"index"`
+	Image  string `json:"image,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Action string `json:"action,omitempty"`
+}
+
+// LoadLayout reads a JSON layout from r, fills the buttons accordingly and
+// binds them to their actions. An error is returned if a layout references
+// an action which has not been registered (see RegisterAction). Buttons not
+// mentioned in the layout are left untouched.
+func (sd *StreamDeck) LoadLayout(r io.Reader) error {
+	var layout Layout
+	if err := json.NewDecoder(r).Decode(&layout); err != nil {
+		return fmt.Errorf("invalid layout: %v", err)
+	}
+
+	for _, key := range layout.Keys {
+		if err := sd.fillLayoutKey(key); err != nil {
+			return fmt.Errorf("key %d: %v", key.Index, err)
+		}
+	}
+	return nil
+}
+
+// fillLayoutKey fills a button with the content described by key and binds
+// it to the action of key.
+func (sd *StreamDeck) fillLayoutKey(key LayoutKey) error {
+	if key.Action != "" {
+		if err := sd.BindKey(key.Index, key.Action); err != nil {
+			return err
+		}
+		if key.Image == "" && key.Text == "" && key.Color == "" {
+			return nil
+		}
+	}
+
+	switch {
+	case key.Image != "" && strings.HasPrefix(key.Image, "data:"):
+		return sd.FillImageFromDataURI(key.Index, key.Image)
+	case key.Image != "":
+		return sd.FillImageFromFile(key.Index, key.Image)
+	}
+
+	bg := color.Color(color.Black)
+	if key.Color != "" {
+		c, err := parseHexColor(key.Color)
+		if err != nil {
+			return err
+		}
+		bg = c
+	}
+
+	if key.Text == "" {
+		r, g, b, _ := bg.RGBA()
+		return sd.FillColor(key.Index, int(r>>8), int(g>>8), int(b>>8))
+	}
+
+	if err := sd.checkValidKeyIndex(key.Index); err != nil {
+		return err
+	}
+	if err := sd.drawKeyState(key.Index, KeyState{Background: bg, Text: key.Text}); err != nil {
+		return err
+	}
+	sd.setKeySource(key.Index, key)
+	return nil
+}
+
+// ExportLayout writes the current content of the buttons as JSON layout to
+// w, so that it can be restored with LoadLayout. Buttons filled with
+// FillColor, FillText, FillImageFromFile, FillImageFromDataURI or through a
+// layout are exported as such; the content of all other buttons is embedded
+// as PNG data URI. The names of the bound actions (see BindKey) are
+// exported as well. Buttons whose content is unknown are omitted, unless
+// they are bound to an action.
+func (sd *StreamDeck) ExportLayout(w io.Writer) error {
+	layout := Layout{Keys: []LayoutKey{}}
+
+	sd.writeMu.Lock()
+	entries := make([]btnCache, len(sd.cache))
+	copy(entries, sd.cache)
+	sd.writeMu.Unlock()
+
+	sd.Lock()
+	bindings := make(map[int]string, len(sd.bindings))
+	for i, name := range sd.bindings {
+		bindings[i] = name
+	}
+	sd.Unlock()
+
+	for i, entry := range entries {
+		key := LayoutKey{Index: i}
+		switch {
+		case entry.src != nil:
+			key = *entry.src
+			key.Index = i
+		case entry.img != nil:
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, entry.img); err != nil {
+				return err
+			}
+			key.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+		case bindings[i] == "":
+			continue
+		}
+		key.Action = bindings[i]
+		layout.Keys = append(layout.Keys, key)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(layout)
+}
+
+// setKeySource records how the content of a button has been created, so
+// that ExportLayout can reproduce it.
+func (sd *StreamDeck) setKeySource(btnIndex int, key LayoutKey) {
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	if btnIndex >= 0 && btnIndex < len(sd.cache) && sd.cache[btnIndex].img != nil {
+		sd.cache[btnIndex].src = &key
+	}
+}
+
+// parseHexColor parses a color in the form "#rrggbb".
+func parseHexColor(s string) (color.RGBA, error) {
+	var r, g, b uint8
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	if _, err := fmt.Sscanf(s[1:], "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	return color.RGBA{r, g, b, 255}, nil
+}
+
+// hexColor formats a color in the form "#rrggbb".
+func hexColor(r, g, b int) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
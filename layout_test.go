@@ -0,0 +1,56 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLayoutActions(t *testing.T) {
+	sd, _ := newTestDeck(t, ProductID)
+	if err := sd.RegisterAction("mute", func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := `{"keys": [
+		{"index": 0, "text": "Mute", "color": "#ff0000", "action": "mute"},
+		{"index": 1, "action": "mute"}
+	]}`
+	if err := sd.LoadLayout(strings.NewReader(layout)); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{0, 1} {
+		if sd.bindings[i] != "mute" {
+			t.Errorf("key %d bound to %q, want %q", i, sd.bindings[i], "mute")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sd.ExportLayout(&buf); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := newTestDeck(t, ProductID)
+	if err := other.RegisterAction("mute", func() {}); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.LoadLayout(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{0, 1} {
+		if other.bindings[i] != "mute" {
+			t.Errorf("exported key %d bound to %q, want %q", i, other.bindings[i], "mute")
+		}
+	}
+	if src := other.cache[0].src; src == nil || src.Text != "Mute" || src.Color != "#ff0000" {
+		t.Errorf("exported key 0 = %+v, want text and color of the layout", src)
+	}
+}
+
+func TestLayoutUnknownAction(t *testing.T) {
+	sd, _ := newTestDeck(t, ProductID)
+
+	err := sd.LoadLayout(strings.NewReader(`{"keys": [{"index": 2, "text": "A", "action": "missing"}]}`))
+	if err == nil || !strings.Contains(err.Error(), "unknown action missing") {
+		t.Errorf("LoadLayout() = %v, want unknown action error", err)
+	}
+}
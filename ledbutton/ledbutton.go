@@ -11,6 +11,7 @@ import (
 	"github.com/gobuffalo/packr/v2"
 
 	sd "github.com/AKovalevich/streamdeck"
+	"github.com/disintegration/gift"
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
 )
@@ -23,8 +24,16 @@ type LedButton struct {
 	textColor  *image.Uniform
 	id         int
 	state      bool
+	mode       sd.BtnMode
 }
 
+const (
+	// Toggle flips the LED on each press of the button.
+	Toggle = sd.Toggle
+	// Momentary turns the LED on while the button is held.
+	Momentary = sd.Momentary
+)
+
 // LEDColor is the type which defines the colors of the LED
 type LEDColor int
 
@@ -115,6 +124,7 @@ func NewLedButton(sd *sd.StreamDeck, id int, options ...func(*LedButton)) (*LedB
 		text:       "",
 		textColor:  image.White,
 		state:      false,
+		mode:       Toggle,
 	}
 
 	for _, option := range options {
@@ -135,17 +145,31 @@ func (btn *LedButton) SetState(state bool) error {
 	return btn.Draw()
 }
 
-// Change
+// Change updates the state of the LED according to the button event and
+// the mode (Toggle by default). The Button is rendered if the
+// state of the LED has changed; errors are logged. Use ChangeErr to handle
+// them yourself.
 func (btn *LedButton) Change(state sd.BtnState) {
-	if state == sd.BtnPressed {
-		btn.state = !btn.state
+	if err := btn.ChangeErr(state); err != nil {
+		btn.streamDeck.Log().Warn(err.Error())
 	}
 }
 
+// ChangeErr updates the state of the LED like Change, but returns the error
+// of rendering the Button.
+func (btn *LedButton) ChangeErr(state sd.BtnState) error {
+	newState := btn.mode.NextState(btn.state, state)
+	if newState == btn.state {
+		return nil
+	}
+	return btn.SetState(newState)
+}
+
 // Draw renders the Button
 func (btn *LedButton) Draw() error {
 
-	img := image.NewRGBA(image.Rect(0, 0, sd.ButtonSize, sd.ButtonSize))
+	size := btn.streamDeck.Capabilities().KeySize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
 	btn.addLED(btn.ledColor, img)
 	if err := btn.addText(btn.text, img); err != nil {
 		return err
@@ -163,21 +187,34 @@ func (btn *LedButton) SetText(text string) error {
 func (btn *LedButton) addLED(color LEDColor, img *image.RGBA) {
 
 	if !btn.state {
-		draw.Draw(img, img.Bounds(), ledOff, image.ZP, draw.Src)
+		drawLED(img, ledOff)
 		return
 	}
 
 	switch color {
 	case LEDRed:
-		draw.Draw(img, img.Bounds(), ledRed, image.ZP, draw.Src)
+		drawLED(img, ledRed)
 	case LEDGreen:
-		draw.Draw(img, img.Bounds(), ledGreen, image.ZP, draw.Src)
+		drawLED(img, ledGreen)
 	case LEDYellow:
-		draw.Draw(img, img.Bounds(), ledYellow, image.ZP, draw.Src)
+		drawLED(img, ledYellow)
 	}
 
 }
 
+// drawLED draws an LED image onto img. The LED images are made for the key
+// size of the original Stream Deck, so they are scaled to other key sizes.
+func drawLED(img *image.RGBA, led image.Image) {
+	size := img.Bounds().Size()
+	if led.Bounds().Size() != size {
+		g := gift.New(gift.Resize(size.X, size.Y, gift.LanczosResampling))
+		scaled := image.NewRGBA(g.Bounds(led.Bounds()))
+		g.Draw(scaled, led)
+		led = scaled
+	}
+	draw.Draw(img, img.Bounds(), led, led.Bounds().Min, draw.Src)
+}
+
 type textParams struct {
 	fontSize float64
 	posX     int
@@ -247,7 +284,10 @@ func (btn *LedButton) addText(text string, img *image.RGBA) error {
 	c.SetClip(img.Bounds())
 	c.SetDst(img)
 	c.SetSrc(btn.textColor)
-	pt := freetype.Pt(p.posX, p.posY+int(c.PointToFixed(24)>>6))
+	// the positions are given for the original key size; keep the text
+	// centered on larger keys
+	offset := (img.Bounds().Dx() - sd.ButtonSize) / 2
+	pt := freetype.Pt(offset+p.posX, offset+p.posY+int(c.PointToFixed(24)>>6))
 
 	if _, err := c.DrawString(text, pt); err != nil {
 		return err
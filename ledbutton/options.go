@@ -1,6 +1,10 @@
 package ledbutton
 
-import "image"
+import (
+	"image"
+
+	sd "github.com/AKovalevich/streamdeck"
+)
 
 // TextColor is a functional option which sets the text color.
 func TextColor(c image.Uniform) func(*LedButton) {
@@ -23,3 +27,11 @@ func Text(text string) func(*LedButton) {
 		btn.text = text
 	}
 }
+
+// Mode is a functional option to set whether the LED toggles on each press
+// (Toggle) or is only on while the button is held (Momentary).
+func Mode(mode sd.BtnMode) func(*LedButton) {
+	return func(btn *LedButton) {
+		btn.mode = mode
+	}
+}
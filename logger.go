@@ -22,33 +22,33 @@ func NewStdLogger() *StdLogger {
 }
 
 func (stdLogger *StdLogger) Debug(args ...interface{}) {
-	log.Print(args)
+	log.Print(args...)
 }
 
 func (stdLogger *StdLogger) Debugf(format string, args ...interface{}) {
-	log.Printf(format, args)
+	log.Printf(format, args...)
 }
 
 func (stdLogger *StdLogger) Info(args ...interface{}) {
-	log.Print(args)
+	log.Print(args...)
 }
 
 func (stdLogger *StdLogger) Infof(format string, args ...interface{}) {
-	log.Printf(format, args)
+	log.Printf(format, args...)
 }
 
 func (stdLogger *StdLogger) Warn(args ...interface{}) {
-	log.Print(args)
+	log.Print(args...)
 }
 
 func (stdLogger *StdLogger) Warnf(format string, args ...interface{}) {
-	log.Printf(format, args)
+	log.Printf(format, args...)
 }
 
 func (stdLogger *StdLogger) Error(args ...interface{}) {
-	log.Print(args)
+	log.Print(args...)
 }
 
 func (stdLogger *StdLogger) Errorf(format string, args ...interface{}) {
-	log.Printf(format, args)
+	log.Printf(format, args...)
 }
@@ -0,0 +1,68 @@
+package StreamDeck
+
+import (
+	"time"
+)
+
+// SetLongPressCb sets a callback which is executed when a button is held for
+// at least duration. The timer starts when the button is pressed and is
+// cancelled when it is released, so the callback is executed at most once
+// per press, while the button is still held. The BtnEvent callback is
+// executed for the press and the release as usual. A nil callback or a
+// duration <= 0 disables the long press detection.
+func (sd *StreamDeck) SetLongPressCb(duration time.Duration, cb func(btnIndex int)) {
+	sd.Lock()
+	defer sd.Unlock()
+
+	sd.stopLongPressTimers()
+	if cb == nil || duration <= 0 {
+		sd.longPressCb = nil
+		sd.longPressDuration = 0
+		return
+	}
+	sd.longPressCb = cb
+	sd.longPressDuration = duration
+}
+
+// trackLongPress starts the long press timer of a button when it is pressed
+// and cancels it when it is released. The lock must be held by the caller.
+func (sd *StreamDeck) trackLongPress(ev Event) {
+	if t, ok := sd.longPressTimers[ev.BtnIndex]; ok {
+		t.Stop()
+		delete(sd.longPressTimers, ev.BtnIndex)
+	}
+	if sd.longPressCb == nil || ev.State != BtnPressed {
+		return
+	}
+	if sd.longPressTimers == nil {
+		sd.longPressTimers = make(map[int]*time.Timer)
+	}
+
+	btnIndex := ev.BtnIndex
+	var t *time.Timer
+	t = time.AfterFunc(sd.longPressDuration, func() {
+		sd.Lock()
+		if sd.longPressTimers[btnIndex] != t {
+			// released (or pressed again) in the meantime
+			sd.Unlock()
+			return
+		}
+		delete(sd.longPressTimers, btnIndex)
+		cb := sd.longPressCb
+		sd.Unlock()
+
+		if cb != nil {
+			cb(btnIndex)
+		}
+	})
+	sd.longPressTimers[btnIndex] = t
+}
+
+// stopLongPressTimers cancels the long press timers of all buttons. The lock
+// must be held by the caller.
+func (sd *StreamDeck) stopLongPressTimers() {
+	for btnIndex, t := range sd.longPressTimers {
+		t.Stop()
+		delete(sd.longPressTimers, btnIndex)
+	}
+}
@@ -0,0 +1,104 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// KeyMask defines the visible shape of the buttons, e.g. to match the
+// rounded bezel of the physical keys. The parts of an image outside of the
+// shape are rendered black. The zero value (NoMask) shows the whole button.
+type KeyMask struct {
+	// radius of the corners relative to ButtonSize
+	radius int
+}
+
+// NoMask shows the whole button.
+var NoMask = KeyMask{}
+
+// maskAlphas caches the alpha channels of the masks per key size.
+var (
+	maskAlphasMu sync.Mutex
+	maskAlphas   = make(map[[2]int]*image.Alpha)
+)
+
+// RoundedCorners returns a KeyMask with rounded corners of the given radius
+// (in pixel of the original Stream Deck; the radius is scaled for models with
+// other key sizes). The edges are anti-aliased.
+func RoundedCorners(radius int) KeyMask {
+	if radius <= 0 {
+		return NoMask
+	}
+	if radius > ButtonSize/2 {
+		radius = ButtonSize / 2
+	}
+	return KeyMask{radius: radius}
+}
+
+// alpha returns the alpha channel of the mask for buttons with the given
+// size.
+func (m KeyMask) alpha(size int) *image.Alpha {
+	maskAlphasMu.Lock()
+	defer maskAlphasMu.Unlock()
+
+	key := [2]int{m.radius, size}
+	if alpha, ok := maskAlphas[key]; ok {
+		return alpha
+	}
+
+	r := float64(m.radius) * float64(size) / ButtonSize
+	edge := float64(size)
+	alpha := image.NewAlpha(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// distance of the pixel center to the nearest point of the
+			// rectangle shrunk by the radius
+			px, py := float64(x)+0.5, float64(y)+0.5
+			cx := math.Max(r, math.Min(edge-r, px))
+			cy := math.Max(r, math.Min(edge-r, py))
+			dist := math.Hypot(px-cx, py-cy)
+			coverage := math.Max(0, math.Min(1, r+0.5-dist))
+			alpha.SetAlpha(x, y, color.Alpha{uint8(coverage*255 + 0.5)})
+		}
+	}
+	maskAlphas[key] = alpha
+	return alpha
+}
+
+// CircleMask returns a KeyMask showing a circle inscribed into the button.
+func CircleMask() KeyMask {
+	return RoundedCorners(ButtonSize / 2)
+}
+
+// SetKeyMask sets the shape applied to all images written to the buttons.
+// The default is NoMask.
+func (sd *StreamDeck) SetKeyMask(mask KeyMask) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.keyMask = mask
+}
+
+// apply darkens the parts of an opaque, square image with the size of a
+// button located outside of the mask.
+func (m KeyMask) apply(img *image.RGBA) {
+	if m.radius == 0 {
+		return
+	}
+	size := img.Bounds().Dx()
+	alpha := m.alpha(size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			a := uint32(alpha.AlphaAt(x, y).A)
+			if a == 0xff {
+				continue
+			}
+			c := img.RGBAAt(x, y)
+			c.R = uint8(uint32(c.R) * a / 0xff)
+			c.G = uint8(uint32(c.G) * a / 0xff)
+			c.B = uint8(uint32(c.B) * a / 0xff)
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Model describes the hardware characteristics of a particular Stream Deck
+// variant.
+type Model struct {
+	// Name is the human readable name of the model.
+	Name string
+	// ProductID is the USB ProductID assigned to the model.
+	ProductID uint16
+	// NumButtons is the total amount of buttons located on the device.
+	NumButtons int
+	// InputReportSize is the size (in bytes) of an input report including
+	// the report ID.
+	InputReportSize int
+	// OutputReportSize is the maximum size (in bytes) of an output report
+	// including the report ID.
+	OutputReportSize int
+
+	// inputReportOffset is the position of the first button state byte
+	// within an input report. The bytes in front of it (e.g. the report ID)
+	// carry no button information.
+	inputReportOffset int
+	// inputBtnMap maps the position of a button state within the input
+	// report to the button index used for writing images. If nil, both
+	// use the same numbering.
+	inputBtnMap []int
+
+	// columns and rows describe the layout of the keys.
+	columns int
+	rows    int
+	// keySize is the edge length (in pixel) of the key displays.
+	keySize int
+	// spacer is the distance (in pixel) between two keys, which is used
+	// to map panel images onto the keys.
+	spacer int
+	// numberedFromRight is true if the keys are numbered from the top
+	// right to the bottom left instead of from the top left.
+	numberedFromRight bool
+	// imageRotation is the rotation the firmware expects for key images.
+	imageRotation KeyRotation
+	// imageFormat is the format in which key images are transmitted.
+	imageFormat ImageFormat
+	// channelOrder is the order of the color channels within a pixel.
+	channelOrder ChannelOrder
+	// numDials is the amount of rotary encoders.
+	numDials int
+	// touchStrip is true if the model has a touch strip.
+	touchStrip bool
+	// protocolVersion is the generation of the image protocol. Version 1
+	// transmits a BMP image in two reports, version 2 splits an image into
+	// pages of imageReportSize bytes.
+	protocolVersion int
+	// pagedBMP is true if an image of protocol version 1 is transmitted
+	// together with its BMP header in pages of imageReportSize bytes
+	// instead of two reports.
+	pagedBMP bool
+	// firmwareReportID is the ID of the feature report containing the
+	// firmware version, which is located at firmwareOffset in the report
+	// of featureReportSize bytes.
+	firmwareReportID  byte
+	firmwareOffset    int
+	featureReportSize int
+	// imageReportSize is the size (in bytes) of an image report of
+	// protocol version 2 or of a paged BMP, including the header.
+	imageReportSize int
+}
+
+// ImageFormat is the format in which a model expects the key images.
+type ImageFormat string
+
+const (
+	// ImageFormatNone is used by models without key displays.
+	ImageFormatNone ImageFormat = ""
+	// ImageFormatBMP is an uncompressed bitmap.
+	ImageFormatBMP ImageFormat = "bmp"
+	// ImageFormatJPEG is a JPEG compressed image.
+	ImageFormatJPEG ImageFormat = "jpeg"
+)
+
+// ChannelOrder is the order in which the color channels of a pixel are
+// transmitted to a Stream Deck.
+type ChannelOrder int
+
+const (
+	// ChannelsRGB transmits red, green, blue.
+	ChannelsRGB ChannelOrder = iota
+	// ChannelsBGR transmits blue, green, red.
+	ChannelsBGR
+	// ChannelsRBG transmits red, blue, green. It is used by the original
+	// Stream Deck.
+	ChannelsRBG
+)
+
+// appendPixel appends the color channels of c to buf in the channel order.
+func (o ChannelOrder) appendPixel(buf []byte, c color.RGBA) []byte {
+	switch o {
+	case ChannelsBGR:
+		return append(buf, c.B, c.G, c.R)
+	case ChannelsRBG:
+		return append(buf, c.R, c.B, c.G)
+	default:
+		return append(buf, c.R, c.G, c.B)
+	}
+}
+
+// pixel returns the opaque color encoded in the three bytes of p. It is
+// the inverse of appendPixel.
+func (o ChannelOrder) pixel(p []byte) color.RGBA {
+	switch o {
+	case ChannelsBGR:
+		return color.RGBA{p[2], p[1], p[0], 255}
+	case ChannelsRBG:
+		return color.RGBA{p[0], p[2], p[1], 255}
+	default:
+		return color.RGBA{p[0], p[1], p[2], 255}
+	}
+}
+
+// Capabilities describe what a Stream Deck model can do. They allow
+// applications to enable features depending on the connected hardware
+// instead of assuming the original 15 key Stream Deck.
+type Capabilities struct {
+	// Model is the human readable name of the model.
+	Model string
+	// NumKeys is the total amount of keys.
+	NumKeys int
+	// Columns and Rows describe the layout of the keys.
+	Columns int
+	Rows    int
+	// HasDisplay is true if the keys are able to show images.
+	HasDisplay bool
+	// KeySize is the edge length (in pixel) of the key displays.
+	KeySize int
+	// ImageFormat is the format in which key images are transmitted.
+	ImageFormat ImageFormat
+	// HasDials is true if the model has rotary encoders.
+	HasDials bool
+	// NumDials is the amount of rotary encoders.
+	NumDials int
+	// HasTouch is true if the model has a touch strip.
+	HasTouch bool
+	// PanelWidth and PanelHeight are the size (in pixel) of an image
+	// covering the whole panel including the spacers, as expected by
+	// FillPanel with PanelGapIncluded.
+	PanelWidth  int
+	PanelHeight int
+}
+
+// modelOriginal is the first generation 15 key Stream Deck. Input reports
+// and image writes both number the buttons from the top right to the bottom
+// left, so no input mapping is necessary.
+var modelOriginal = Model{
+	Name:            "Stream Deck",
+	ProductID:       ProductID,
+	NumButtons:      NumButtons,
+	InputReportSize: 17,
+	// the firmware accepts image reports of up to 8191 bytes
+	OutputReportSize: 8191,
+	// the input report starts with the report ID (0x01). Depending on the
+	// firmware revision it is followed by the 15 button states and an
+	// optional trailing padding byte.
+	inputReportOffset: 1,
+	columns:           NumButtonColumns,
+	rows:              NumButtonRows,
+	keySize:           ButtonSize,
+	spacer:            Spacer,
+	numberedFromRight: true,
+	imageFormat:       ImageFormatBMP,
+	channelOrder:      ChannelsRBG,
+	protocolVersion:   1,
+	firmwareReportID:  0x04,
+	firmwareOffset:    5,
+	featureReportSize: 17,
+}
+
+// modelXL is the Stream Deck XL with 32 keys. The keys are numbered from
+// the top left in input reports and image writes. Images are transmitted as
+// JPEG, rotated by 180°, in pages of 1024 bytes.
+var modelXL = Model{
+	Name:              "Stream Deck XL",
+	ProductID:         ProductIDXL,
+	NumButtons:        32,
+	InputReportSize:   512,
+	OutputReportSize:  1024,
+	inputReportOffset: 4,
+	columns:           8,
+	rows:              4,
+	keySize:           96,
+	spacer:            32,
+	imageFormat:       ImageFormatJPEG,
+	channelOrder:      ChannelsRGB,
+	imageRotation:     Rotate180,
+	protocolVersion:   2,
+	imageReportSize:   1024,
+	firmwareReportID:  0x05,
+	firmwareOffset:    6,
+	featureReportSize: 32,
+}
+
+// modelMini is the Stream Deck Mini with 6 keys. The keys are numbered from
+// the top left in input reports and image writes. Images are transmitted as
+// BMP including the BMP header, rotated by 90°, in pages of 1024 bytes.
+var modelMini = Model{
+	Name:              "Stream Deck Mini",
+	ProductID:         ProductIDMini,
+	NumButtons:        6,
+	InputReportSize:   17,
+	OutputReportSize:  1024,
+	inputReportOffset: 1,
+	columns:           3,
+	rows:              2,
+	keySize:           80,
+	// the physical gaps are as wide as on the original Stream Deck
+	spacer:            Spacer * 80 / ButtonSize,
+	imageFormat:       ImageFormatBMP,
+	channelOrder:      ChannelsBGR,
+	imageRotation:     Rotate90,
+	protocolVersion:   1,
+	pagedBMP:          true,
+	imageReportSize:   1024,
+	firmwareReportID:  0x04,
+	firmwareOffset:    5,
+	featureReportSize: 17,
+}
+
+// models contains all supported Stream Deck models.
+var models = []Model{
+	modelOriginal,
+	modelXL,
+	modelMini,
+}
+
+// modelForProductID returns the Model with the given USB ProductID.
+func modelForProductID(productID uint16) (Model, error) {
+	for _, m := range models {
+		if m.ProductID == productID {
+			return m, nil
+		}
+	}
+	return Model{}, fmt.Errorf("unsupported stream deck product id 0x%04x", productID)
+}
+
+// buttonStates extracts the button state bytes from an input report.
+// Only the bytes actually received have to be provided. If the report is
+// shorter than expected, only the available button states are returned.
+func (m Model) buttonStates(report []byte) []byte {
+	if len(report) <= m.inputReportOffset {
+		return nil
+	}
+	data := report[m.inputReportOffset:]
+	if len(data) > m.NumButtons {
+		data = data[:m.NumButtons]
+	}
+	return data
+}
+
+// btnIndex maps the position of a button state within the input report to
+// the button index used for writing images.
+func (m Model) btnIndex(reportPos int) int {
+	if m.inputBtnMap == nil || reportPos >= len(m.inputBtnMap) {
+		return reportPos
+	}
+	return m.inputBtnMap[reportPos]
+}
+
+// capabilities returns the Capabilities of the model.
+func (m Model) capabilities() Capabilities {
+	return Capabilities{
+		Model:       m.Name,
+		NumKeys:     m.NumButtons,
+		Columns:     m.columns,
+		Rows:        m.rows,
+		HasDisplay:  m.imageFormat != ImageFormatNone,
+		KeySize:     m.keySize,
+		ImageFormat: m.imageFormat,
+		HasDials:    m.numDials > 0,
+		NumDials:    m.numDials,
+		HasTouch:    m.touchStrip,
+		PanelWidth:  m.panelRect().Dx(),
+		PanelHeight: m.panelRect().Dy(),
+	}
+}
+
+// validKeyIndex returns an error if the model has no key with the index.
+func (m Model) validKeyIndex(btnIndex int) error {
+	if btnIndex < 0 || btnIndex >= m.NumButtons {
+		return fmt.Errorf("invalid key index")
+	}
+	return nil
+}
+
+// btnRect returns the position of a key in panel coordinates.
+func (m Model) btnRect(btnIndex int) image.Rectangle {
+	row := btnIndex / m.columns
+	col := btnIndex % m.columns
+	if m.numberedFromRight {
+		col = m.columns - 1 - col
+	}
+	x := col * (m.keySize + m.spacer)
+	y := row * (m.keySize + m.spacer)
+	return image.Rect(x, y, x+m.keySize, y+m.keySize)
+}
+
+// panelRect returns the size of the panel including the spacers.
+func (m Model) panelRect() image.Rectangle {
+	return image.Rect(0, 0,
+		m.columns*m.keySize+m.spacer*(m.columns-1),
+		m.rows*m.keySize+m.spacer*(m.rows-1))
+}
+
+// readingOrder returns the index of the key at the position pos, counted
+// in reading order from the top left.
+func (m Model) readingOrder(pos int) int {
+	row := pos / m.columns
+	col := pos % m.columns
+	if m.numberedFromRight {
+		col = m.columns - 1 - col
+	}
+	return row*m.columns + col
+}
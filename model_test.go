@@ -0,0 +1,94 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestButtonStates(t *testing.T) {
+	states := make([]byte, NumButtons)
+	states[0] = 1
+	states[NumButtons-1] = 1
+
+	report := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+
+	tests := []struct {
+		name   string
+		report []byte
+		want   []byte
+	}{
+		{"report ID, states and padding", report([]byte{0x01}, states, []byte{0x00}), states},
+		{"report ID and states", report([]byte{0x01}, states), states},
+		{"truncated states", report([]byte{0x01}, states[:4]), states[:4]},
+		{"report ID only", []byte{0x01}, nil},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modelOriginal.buttonStates(tt.report)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("buttonStates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateBtnStatesReportRevisions(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"17 byte report", 17},
+		{"16 byte report", 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, err := NewStreamDeckWithDevice(nil, NewVirtualDevice())
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := make([]byte, tt.size)
+			report[0] = 0x01
+			report[1] = 1
+			report[15] = 1
+
+			events, _ := sd.updateBtnStates(report)
+			if len(events) != 2 {
+				t.Fatalf("got %d events, want 2", len(events))
+			}
+			for i, want := range []int{0, 14} {
+				if events[i].BtnIndex != want || events[i].State != BtnPressed {
+					t.Errorf("event %d = button %d %v, want button %d pressed",
+						i, events[i].BtnIndex, events[i].State, want)
+				}
+			}
+		})
+	}
+}
+
+func TestChannelOrder(t *testing.T) {
+	c := color.RGBA{0x11, 0x22, 0x33, 0xff}
+	tests := []struct {
+		model Model
+		want  []byte
+	}{
+		{modelOriginal, []byte{0x11, 0x33, 0x22}},
+		{modelXL, []byte{0x11, 0x22, 0x33}},
+		{modelMini, []byte{0x33, 0x22, 0x11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model.Name, func(t *testing.T) {
+			order := tt.model.channelOrder
+			got := order.appendPixel([]byte{0x00}, c)
+			if !bytes.Equal(got[1:], tt.want) {
+				t.Errorf("appendPixel() = % x, want % x", got[1:], tt.want)
+			}
+			if p := order.pixel(tt.want); p != c {
+				t.Errorf("pixel(% x) = %v, want %v", tt.want, p, c)
+			}
+		})
+	}
+}
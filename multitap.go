@@ -0,0 +1,82 @@
+package StreamDeck
+
+import (
+	"time"
+)
+
+// multiTap counts the taps of a button within the current window.
+type multiTap struct {
+	taps  int
+	timer *time.Timer
+}
+
+// SetMultiTapCb sets a callback which reports how often a button has been
+// tapped in quick succession, e.g. to tell a double tap from a single one.
+// Every press of a button opens (or extends) a window of the given duration;
+// when the window expires without another press, the callback is executed
+// with the amount of taps. A button which is still held when the window
+// expires is reported with the taps so far, including the held one; its
+// release isn't counted again. The BtnEvent callback is executed for every
+// press and release as usual. A nil callback or a window <= 0 disables the
+// tap counting.
+func (sd *StreamDeck) SetMultiTapCb(window time.Duration, cb func(btnIndex, taps int)) {
+	sd.Lock()
+	defer sd.Unlock()
+
+	sd.stopMultiTaps()
+	if cb == nil || window <= 0 {
+		sd.multiTapCb = nil
+		sd.multiTapWindow = 0
+		return
+	}
+	sd.multiTapCb = cb
+	sd.multiTapWindow = window
+}
+
+// countTap counts a press of a button and (re)starts its tap window. The
+// lock must be held by the caller.
+func (sd *StreamDeck) countTap(ev Event) {
+	if sd.multiTapCb == nil || ev.State != BtnPressed {
+		return
+	}
+	if sd.multiTaps == nil {
+		sd.multiTaps = make(map[int]*multiTap)
+	}
+
+	btnIndex := ev.BtnIndex
+	mt, ok := sd.multiTaps[btnIndex]
+	if ok {
+		mt.timer.Stop()
+	} else {
+		mt = &multiTap{}
+		sd.multiTaps[btnIndex] = mt
+	}
+	mt.taps++
+	var t *time.Timer
+	t = time.AfterFunc(sd.multiTapWindow, func() {
+		sd.Lock()
+		if sd.multiTaps[btnIndex] != mt || mt.timer != t {
+			// pressed again or reset in the meantime
+			sd.Unlock()
+			return
+		}
+		delete(sd.multiTaps, btnIndex)
+		taps := mt.taps
+		cb := sd.multiTapCb
+		sd.Unlock()
+
+		if cb != nil {
+			cb(btnIndex, taps)
+		}
+	})
+	mt.timer = t
+}
+
+// stopMultiTaps discards the taps counted so far on all buttons. The lock
+// must be held by the caller.
+func (sd *StreamDeck) stopMultiTaps() {
+	for btnIndex, mt := range sd.multiTaps {
+		mt.timer.Stop()
+		delete(sd.multiTaps, btnIndex)
+	}
+}
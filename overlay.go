@@ -0,0 +1,83 @@
+package StreamDeck
+
+import (
+	"image"
+	"image/draw"
+)
+
+// overlay is a temporary image shown on top of the content of a button.
+type overlay struct {
+	// saved is the content of the button before the overlay was shown
+	saved *image.RGBA
+}
+
+// OverlayKey temporarily shows an image on a button, e.g. for "press again
+// to confirm" flows. The content currently shown on the button is saved and
+// written back when the returned restore function is called. Overlays can
+// be nested; each restore function reverts its own overlay, even if the
+// overlays are restored out of order. Calling restore more than once has no
+// effect.
+func (sd *StreamDeck) OverlayKey(btnIndex int, img image.Image) (restore func() error) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		sd.log.Warn(err.Error())
+		return func() error { return err }
+	}
+
+	sd.writeMu.Lock()
+	saved := sd.cachedImage(btnIndex)
+	if saved == nil {
+		saved = image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
+		draw.Draw(saved, saved.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+	}
+	o := &overlay{saved: saved}
+	if sd.overlays == nil {
+		sd.overlays = make(map[int][]*overlay)
+	}
+	sd.overlays[btnIndex] = append(sd.overlays[btnIndex], o)
+	sd.writeMu.Unlock()
+
+	if err := sd.FillImage(btnIndex, img); err != nil {
+		sd.log.Warn(err.Error())
+	}
+
+	return func() error {
+		return sd.restoreOverlay(btnIndex, o)
+	}
+}
+
+// restoreOverlay removes an overlay from the stack of a button. If it is
+// the topmost overlay, the saved content is written back; otherwise the
+// overlay above takes over the saved content.
+func (sd *StreamDeck) restoreOverlay(btnIndex int, o *overlay) error {
+	sd.writeMu.Lock()
+	stack := sd.overlays[btnIndex]
+	pos := -1
+	for i, entry := range stack {
+		if entry == o {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		// already restored
+		sd.writeMu.Unlock()
+		return nil
+	}
+
+	top := pos == len(stack)-1
+	if !top {
+		stack[pos+1].saved = o.saved
+	}
+	stack = append(stack[:pos], stack[pos+1:]...)
+	if len(stack) == 0 {
+		delete(sd.overlays, btnIndex)
+	} else {
+		sd.overlays[btnIndex] = stack
+	}
+	sd.writeMu.Unlock()
+
+	if !top {
+		return nil
+	}
+	return sd.FillImage(btnIndex, o.saved)
+}
@@ -0,0 +1,174 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PageManager keeps track of the pages shown on the Stream Deck. Pages are
+// organized as a stack; the page on top of the stack is the active page
+// and receives all button events. Pages can be registered with a string ID
+// to navigate to them directly with GotoPage.
+type PageManager struct {
+	sync.Mutex
+	sd    *StreamDeck
+	stack []Page
+	pages map[string]Page
+}
+
+// NewPageManager is the constructor of a PageManager. The root page becomes
+// the active page and is drawn immediately.
+func NewPageManager(sd *StreamDeck, root Page) (*PageManager, error) {
+	if sd == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+	if root == nil {
+		return nil, fmt.Errorf("root page must not be nil")
+	}
+
+	pm := &PageManager{
+		sd:    sd,
+		stack: []Page{root},
+		pages: make(map[string]Page),
+	}
+
+	pm.activate(root)
+
+	return pm, nil
+}
+
+// Current returns the active page.
+func (pm *PageManager) Current() Page {
+	pm.Lock()
+	defer pm.Unlock()
+	return pm.stack[len(pm.stack)-1]
+}
+
+// AddPage registers a page with an ID, so that it can be activated with
+// GotoPage. The root page can be registered as well.
+func (pm *PageManager) AddPage(id string, p Page) error {
+	if id == "" {
+		return fmt.Errorf("page id must not be empty")
+	}
+	if p == nil {
+		return fmt.Errorf("page must not be nil")
+	}
+
+	pm.Lock()
+	defer pm.Unlock()
+
+	if _, ok := pm.pages[id]; ok {
+		return fmt.Errorf("page %s already registered", id)
+	}
+	pm.pages[id] = p
+	return nil
+}
+
+// CurrentPageID returns the ID of the active page or an empty string if the
+// active page has not been registered with AddPage.
+func (pm *PageManager) CurrentPageID() string {
+	pm.Lock()
+	defer pm.Unlock()
+
+	current := pm.stack[len(pm.stack)-1]
+	for id, p := range pm.pages {
+		if p == current {
+			return id
+		}
+	}
+	return ""
+}
+
+// GotoPage activates the page registered with id, independent of the page
+// which is currently active. The stack is rebuilt from the Parent chain of
+// the page on top of the root page, so that navigating back works as if the
+// page had been reached step by step.
+func (pm *PageManager) GotoPage(id string) error {
+	pm.Lock()
+	defer pm.Unlock()
+
+	p, ok := pm.pages[id]
+	if !ok {
+		return fmt.Errorf("unknown page %s", id)
+	}
+
+	root := pm.stack[0]
+	var chain []Page
+	for page := p; page != nil && page != root; page = page.Parent() {
+		chain = append(chain, page)
+	}
+
+	stack := []Page{root}
+	for i := len(chain) - 1; i >= 0; i-- {
+		stack = append(stack, chain[i])
+	}
+
+	pm.stack[len(pm.stack)-1].SetActive(false)
+	pm.stack = stack
+	pm.activate(p)
+
+	return nil
+}
+
+// Push makes the page the active page and draws it.
+func (pm *PageManager) Push(p Page) error {
+	if p == nil {
+		return fmt.Errorf("page must not be nil")
+	}
+
+	pm.Lock()
+	defer pm.Unlock()
+
+	pm.stack[len(pm.stack)-1].SetActive(false)
+	pm.stack = append(pm.stack, p)
+	pm.activate(p)
+
+	return nil
+}
+
+// Pop removes the active page from the stack and activates the page
+// below. The root page can not be removed.
+func (pm *PageManager) Pop() error {
+	pm.Lock()
+	defer pm.Unlock()
+
+	if len(pm.stack) <= 1 {
+		return fmt.Errorf("root page can not be removed")
+	}
+
+	pm.stack[len(pm.stack)-1].SetActive(false)
+	pm.stack = pm.stack[:len(pm.stack)-1]
+	pm.activate(pm.stack[len(pm.stack)-1])
+
+	return nil
+}
+
+// Handle forwards a button event to the active page. If the page returns
+// its parent, the active page is popped from the stack; any other page
+// returned is pushed on top. Handle can be used directly as BtnEvent
+// callback.
+func (pm *PageManager) Handle(btnIndex int, state BtnState) {
+	current := pm.Current()
+
+	next := current.Set(btnIndex, state)
+	if next == nil || next == current {
+		return
+	}
+
+	var err error
+	if next == current.Parent() {
+		err = pm.Pop()
+	} else {
+		err = pm.Push(next)
+	}
+	if err != nil {
+		pm.sd.log.Warn(err.Error())
+	}
+}
+
+// activate clears the panel and draws the page.
+func (pm *PageManager) activate(p Page) {
+	pm.sd.ClearAllBtns()
+	p.SetActive(true)
+	p.Draw()
+}
@@ -0,0 +1,123 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PreloadError contains the errors which occurred while loading the images
+// with PreloadImages, indexed by button.
+type PreloadError map[int]error
+
+func (e PreloadError) Error() string {
+	btns := make([]int, 0, len(e))
+	for btnIndex := range e {
+		btns = append(btns, btnIndex)
+	}
+	sort.Ints(btns)
+
+	msgs := make([]string, 0, len(btns))
+	for _, btnIndex := range btns {
+		msgs = append(msgs, fmt.Sprintf("button %d: %v", btnIndex, e[btnIndex]))
+	}
+	return "preloading images failed: " + strings.Join(msgs, "; ")
+}
+
+// PreloadImages fills several buttons with images from files. The paths are
+// indexed by button. Unlike calling FillImageFromFile repeatedly, the files
+// are read, decoded and scaled concurrently by a bounded pool of workers;
+// afterwards the images are sent to the Stream Deck. A file which can not be
+// loaded doesn't prevent the other buttons from being filled. All errors
+// are returned as PreloadError.
+func (sd *StreamDeck) PreloadImages(paths map[int]string) error {
+	errs := make(PreloadError)
+	jobs := make(map[int]string, len(paths))
+	for btnIndex, path := range paths {
+		if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+			errs[btnIndex] = err
+			continue
+		}
+		jobs[btnIndex] = path
+	}
+
+	sd.Lock()
+	scaleMode := sd.scaleMode
+	sd.Unlock()
+
+	type result struct {
+		btnIndex int
+		img      image.Image
+		err      error
+	}
+
+	workers := runtime.NumCPU()
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	jobChan := make(chan int)
+	resChan := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for btnIndex := range jobChan {
+				img, err := decodeImageFile(jobs[btnIndex])
+				if err == nil {
+					rect := img.Bounds()
+					if rect.Dx() != sd.model.keySize || rect.Dy() != sd.model.keySize {
+						img = sd.scale(img, sd.model.keySize, sd.model.keySize, scaleMode)
+					}
+				}
+				resChan <- result{btnIndex, img, err}
+			}
+		}()
+	}
+
+	go func() {
+		for btnIndex := range jobs {
+			jobChan <- btnIndex
+		}
+		close(jobChan)
+		wg.Wait()
+		close(resChan)
+	}()
+
+	imgs := make(map[int]image.Image, len(jobs))
+	for res := range resChan {
+		if res.err != nil {
+			errs[res.btnIndex] = res.err
+			continue
+		}
+		imgs[res.btnIndex] = res.img
+	}
+
+	for btnIndex, img := range imgs {
+		if err := sd.FillImage(btnIndex, img); err != nil {
+			errs[btnIndex] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// decodeImageFile reads and decodes an image file.
+func decodeImageFile(path string) (image.Image, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	img, _, err := image.Decode(reader)
+	return img, err
+}
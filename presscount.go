@@ -0,0 +1,45 @@
+package StreamDeck
+
+// SetPressCounting enables or disables counting the presses of every
+// button (see PressCount). Counting is disabled by default. Disabling it
+// discards the counts.
+func (sd *StreamDeck) SetPressCounting(enabled bool) {
+	sd.Lock()
+	defer sd.Unlock()
+	switch {
+	case enabled && sd.pressCounts == nil:
+		sd.pressCounts = make([]int, len(sd.btnState))
+	case !enabled:
+		sd.pressCounts = nil
+	}
+}
+
+// PressCount returns how often a button has been pressed since press
+// counting has been enabled with SetPressCounting or the count has been
+// reset. Presses dropped by the event throttle are not counted. If press
+// counting is disabled, 0 is returned.
+func (sd *StreamDeck) PressCount(btnIndex int) int {
+	sd.Lock()
+	defer sd.Unlock()
+	if btnIndex < 0 || btnIndex >= len(sd.pressCounts) {
+		return 0
+	}
+	return sd.pressCounts[btnIndex]
+}
+
+// ResetPressCount resets the press count of a button to 0.
+func (sd *StreamDeck) ResetPressCount(btnIndex int) {
+	sd.Lock()
+	defer sd.Unlock()
+	if btnIndex >= 0 && btnIndex < len(sd.pressCounts) {
+		sd.pressCounts[btnIndex] = 0
+	}
+}
+
+// countPress increments the press count of a button if press counting is
+// enabled. The lock must be held by the caller.
+func (sd *StreamDeck) countPress(btnIndex int) {
+	if btnIndex >= 0 && btnIndex < len(sd.pressCounts) {
+		sd.pressCounts[btnIndex]++
+	}
+}
@@ -0,0 +1,149 @@
+package StreamDeck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// RecordKind is the kind of operation captured by a RecordingDevice.
+type RecordKind int
+
+const (
+	// RecordWrite is an output report sent with Write.
+	RecordWrite RecordKind = iota
+	// RecordFeatureReport is a feature report sent with SendFeatureReport.
+	RecordFeatureReport
+)
+
+// WriteRecord describes one write operation captured by a RecordingDevice.
+type WriteRecord struct {
+	Kind RecordKind
+	// Data is a copy of the bytes which have been sent.
+	Data []byte
+	// Time is the point in time when the operation has been started.
+	Time time.Time
+	// Err is the error returned by the underlying device.
+	Err error
+}
+
+// RecordingDevice wraps a Device and records every Write and
+// SendFeatureReport call, including its bytes and timestamp. All calls are
+// passed through to the wrapped device. It allows tests to assert the exact
+// order and content of operations, e.g. that FillImage always sends the
+// first page of an image before the second one.
+type RecordingDevice struct {
+	Device
+	mu      sync.Mutex
+	records []WriteRecord
+}
+
+// NewRecordingDevice returns a RecordingDevice wrapping device. Use a
+// VirtualDevice to record without hardware.
+func NewRecordingDevice(device Device) *RecordingDevice {
+	return &RecordingDevice{Device: device}
+}
+
+// Write passes the output report to the wrapped device and records it.
+func (rd *RecordingDevice) Write(data []byte) (int, error) {
+	start := time.Now()
+	n, err := rd.Device.Write(data)
+	rd.record(RecordWrite, data, start, err)
+	return n, err
+}
+
+// SendFeatureReport passes the feature report to the wrapped device and
+// records it.
+func (rd *RecordingDevice) SendFeatureReport(data []byte) error {
+	start := time.Now()
+	err := rd.Device.SendFeatureReport(data)
+	rd.record(RecordFeatureReport, data, start, err)
+	return err
+}
+
+// ReadContext reads from the wrapped device like Read. If the wrapped
+// device isn't a ContextReader, ctx is ignored (see CanCancelRead).
+func (rd *RecordingDevice) ReadContext(ctx context.Context, data []byte) (int, error) {
+	if d, ok := rd.Device.(ContextReader); ok {
+		return d.ReadContext(ctx, data)
+	}
+	return rd.Device.Read(data)
+}
+
+// CanCancelRead returns true if the wrapped device is a ContextReader whose
+// reads can be aborted (see ReadCanceler).
+func (rd *RecordingDevice) CanCancelRead() bool {
+	return canCancelRead(rd.Device)
+}
+
+// SetReadTimeout sets the read timeout of the wrapped device (if it
+// supports read timeouts).
+func (rd *RecordingDevice) SetReadTimeout(timeout time.Duration) {
+	if d, ok := rd.Device.(readTimeouter); ok {
+		d.SetReadTimeout(timeout)
+	}
+}
+
+// GetFeatureReport reads a feature report from the wrapped device (if it is
+// a FeatureReporter). Reads are not recorded.
+func (rd *RecordingDevice) GetFeatureReport(data []byte) (int, error) {
+	if d, ok := rd.Device.(FeatureReporter); ok {
+		return d.GetFeatureReport(data)
+	}
+	return 0, errors.New("device does not support reading feature reports")
+}
+
+// GetUSBPath returns the USB path of the wrapped device (if available).
+func (rd *RecordingDevice) GetUSBPath() string {
+	if d, ok := rd.Device.(interface{ GetUSBPath() string }); ok {
+		return d.GetUSBPath()
+	}
+	return ""
+}
+
+// GetMaxPacketSize returns the max packet size of the wrapped device (if
+// available).
+func (rd *RecordingDevice) GetMaxPacketSize() int {
+	if d, ok := rd.Device.(interface{ GetMaxPacketSize() int }); ok {
+		return d.GetMaxPacketSize()
+	}
+	return 0
+}
+
+// PrefixesReportID returns whether the wrapped device adds the report ID to
+// output reports itself (see ReportIDPrefixer).
+func (rd *RecordingDevice) PrefixesReportID() bool {
+	if d, ok := rd.Device.(ReportIDPrefixer); ok {
+		return d.PrefixesReportID()
+	}
+	return false
+}
+
+// Records returns a copy of all operations recorded so far, in the order in
+// which they have been executed.
+func (rd *RecordingDevice) Records() []WriteRecord {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	res := make([]WriteRecord, len(rd.records))
+	copy(res, rd.records)
+	return res
+}
+
+// Reset discards all recorded operations.
+func (rd *RecordingDevice) Reset() {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.records = nil
+}
+
+func (rd *RecordingDevice) record(kind RecordKind, data []byte, start time.Time, err error) {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.records = append(rd.records, WriteRecord{
+		Kind: kind,
+		Data: append([]byte(nil), data...),
+		Time: start,
+		Err:  err,
+	})
+}
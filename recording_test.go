@@ -0,0 +1,34 @@
+package StreamDeck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// plainDevice hides the optional interfaces of the wrapped device.
+type plainDevice struct {
+	Device
+}
+
+func TestRecordingDeviceReadTimeout(t *testing.T) {
+	vd := NewVirtualDevice()
+	sd, err := NewStreamDeckWithDevice(nil, NewRecordingDevice(vd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sd.SetReadTimeout(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vd.ReadContext(context.Background(), make([]byte, 17)); err != ErrReadTimeout {
+		t.Errorf("read of the wrapped device returned %v, want ErrReadTimeout", err)
+	}
+
+	plain, err := NewStreamDeckWithDevice(nil, NewRecordingDevice(plainDevice{NewVirtualDevice()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.SetReadTimeout(time.Second); err == nil {
+		t.Error("SetReadTimeout succeeded for a device without read timeouts")
+	}
+}
@@ -0,0 +1,216 @@
+// Package rest exposes a Stream Deck through a small HTTP API, so that it can
+// be driven by scripts in any language. It is built on the public API of the
+// streamdeck package only. The endpoints are:
+//
+//	POST /keys/{i}/image  fill button i with the image (png, jpeg or gif)
+//	                      contained in the request body
+//	POST /keys/{i}/text   write text on button i; the body is a JSON object
+//	                      like {"text": "Hello"}
+//	POST /brightness      set the brightness; the body is a JSON object
+//	                      like {"brightness": 50}
+//	GET  /events          stream the button events as server-sent events
+//
+// Successful requests are answered with 204 No Content. Errors are answered
+// with a JSON object like {"error": "invalid key index"}.
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sd "github.com/AKovalevich/streamdeck"
+)
+
+// MaxImageSize is the maximum size (in bytes) of an image uploaded to
+// /keys/{i}/image.
+const MaxImageSize = 4 << 20
+
+// maxJSONSize is the maximum size (in bytes) of a JSON request body.
+const maxJSONSize = 64 << 10
+
+// Event is sent to the clients of /events for every button event.
+type Event struct {
+	// Btn is the index of the button.
+	Btn int `json:"btn"`
+	// State is "pressed" or "released".
+	State string `json:"state"`
+}
+
+// TextRequest is the body of a request to /keys/{i}/text.
+type TextRequest struct {
+	// Text is drawn centered with the embedded default font (see
+	// StreamDeck.FillText). Lines are separated by "\n".
+	Text string `json:"text"`
+}
+
+// BrightnessRequest is the body of a request to /brightness.
+type BrightnessRequest struct {
+	// Brightness is the brightness in percent (0-100).
+	Brightness *int `json:"brightness"`
+}
+
+// Server is a http.Handler serving the REST API.
+type Server struct {
+	streamDeck *sd.StreamDeck
+}
+
+// NewServer is the constructor of a Server for the given Stream Deck.
+func NewServer(streamDeck *sd.StreamDeck) (*Server, error) {
+	if streamDeck == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+	return &Server{
+		streamDeck: streamDeck,
+	}, nil
+}
+
+// ServeHTTP dispatches the request to the endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 3 && parts[0] == "keys" && (parts[2] == "image" || parts[2] == "text"):
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		btnIndex, err := strconv.Atoi(parts[1])
+		if err != nil || btnIndex < 0 || btnIndex >= s.streamDeck.Capabilities().NumKeys {
+			writeError(w, http.StatusBadRequest, "invalid key index %q", parts[1])
+			return
+		}
+		if parts[2] == "image" {
+			s.handleImage(w, r, btnIndex)
+		} else {
+			s.handleText(w, r, btnIndex)
+		}
+	case len(parts) == 1 && parts[0] == "brightness":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		s.handleBrightness(w, r)
+	case len(parts) == 1 && parts[0] == "events":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		s.handleEvents(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint %s", r.URL.Path)
+	}
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request, btnIndex int) {
+	body := http.MaxBytesReader(w, r.Body, MaxImageSize)
+	img, _, err := image.Decode(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to decode image: %v", err)
+		return
+	}
+	if err := s.streamDeck.FillImage(btnIndex, img); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleText(w http.ResponseWriter, r *http.Request, btnIndex int) {
+	var req TextRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := s.streamDeck.FillText(btnIndex, req.Text); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleBrightness(w http.ResponseWriter, r *http.Request) {
+	var req BrightnessRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Brightness == nil {
+		writeError(w, http.StatusBadRequest, "missing brightness")
+		return
+	}
+	if *req.Brightness < 0 || *req.Brightness > 100 {
+		writeError(w, http.StatusBadRequest, "brightness %d out of range 0-100", *req.Brightness)
+		return
+	}
+	if err := s.streamDeck.SetBrightness(*req.Brightness); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEvents streams the button events until the client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	events, cancel := s.streamDeck.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case ev := <-events:
+			data, err := json.Marshal(Event{Btn: ev.BtnIndex, State: stateName(ev.State)})
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: button\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// decodeJSON decodes the JSON body of a request into v. If it fails, the
+// error is written to the client and false is returned.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("empty request body")
+		}
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{fmt.Sprintf(format, args...)})
+}
+
+func stateName(state sd.BtnState) string {
+	if state == sd.BtnPressed {
+		return "pressed"
+	}
+	return "released"
+}
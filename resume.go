@@ -0,0 +1,100 @@
+package StreamDeck
+
+import (
+	"time"
+)
+
+// DefaultResumeWindow is the default maximum duration between the loss of
+// the connection and the reappearance of the same Stream Deck for which the
+// reconnect is treated as a resume (see OnResume).
+const DefaultResumeWindow = 10 * time.Second
+
+// OnResume sets a callback which gets executed when the Stream Deck
+// reappears shortly after the connection has been lost, like it happens
+// when the computer is suspended and resumed. A reconnect is treated as a
+// resume if the device with the same serial number is back within the
+// resume window (see SetResumeWindow). On a resume the buttons are not
+// cleared; instead the content displayed before the connection was lost is
+// restored, and OnResume is executed instead of OnConnect. Any other
+// reconnect is treated like a new connection.
+func (sd *StreamDeck) OnResume(callback func()) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.onResumeCallback = callback
+}
+
+// SetResumeWindow sets the maximum duration between the loss of the
+// connection and the reconnect for which the reconnect is treated as a
+// resume (see OnResume). A window of zero disables the resume detection.
+// The default is DefaultResumeWindow.
+func (sd *StreamDeck) SetResumeWindow(window time.Duration) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.resumeWindow = window
+}
+
+// markDisconnected records when the connection to the device has been
+// lost. Only the first loss is recorded until the device is reconnected.
+func (sd *StreamDeck) markDisconnected() {
+	sd.Lock()
+	defer sd.Unlock()
+	if sd.disconnectedAt.IsZero() {
+		sd.disconnectedAt = time.Now()
+	}
+}
+
+// checkResume is called after the device has been connected again. It
+// returns true if the same device reappeared within the resume window.
+func (sd *StreamDeck) checkResume() bool {
+	serial, err := sd.device.GetSerialNumber()
+
+	sd.Lock()
+	defer sd.Unlock()
+
+	prevSerial := sd.serial
+	lost := sd.disconnectedAt
+	sd.disconnectedAt = time.Time{}
+	if err == nil {
+		sd.serial = serial
+	}
+
+	if err != nil || serial == "" || serial != prevSerial || lost.IsZero() {
+		return false
+	}
+	return sd.resumeWindow > 0 && time.Since(lost) <= sd.resumeWindow
+}
+
+// resume restores the content of the buttons after a resume and executes
+// the OnResume callback.
+func (sd *StreamDeck) resume() {
+	sd.writeMu.Lock()
+	err := sd.restoreBtns()
+	sd.writeMu.Unlock()
+	if err != nil {
+		sd.log.Warnf("unable to restore buttons after resume: %v", err)
+	}
+	sd.setReady(true)
+
+	sd.Lock()
+	cb := sd.onResumeCallback
+	sd.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
+
+// restoreBtns sends the cached content of all buttons to the device again.
+// Buttons whose content is unknown are cleared. The write lock must be held
+// by the caller.
+func (sd *StreamDeck) restoreBtns() error {
+	for i := range sd.cache {
+		buf := sd.cache[i].buf
+		if buf == nil {
+			buf = sd.blankBtnBuf()
+		}
+		if err := sd.sendBtnBuf(i, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package StreamDeck
+
+import (
+	"image"
+
+	"github.com/disintegration/gift"
+)
+
+// KeyRotation is the angle by which the content of the buttons is rotated
+// clockwise, e.g. to compensate for a Stream Deck mounted sideways.
+type KeyRotation int
+
+const (
+	// Rotate0 doesn't rotate the content of the buttons.
+	Rotate0 KeyRotation = iota
+	// Rotate90 rotates the content of the buttons by 90° clockwise. It
+	// compensates a deck rotated by 90° counter-clockwise.
+	Rotate90
+	// Rotate180 rotates the content of the buttons by 180°.
+	Rotate180
+	// Rotate270 rotates the content of the buttons by 270° clockwise
+	// (90° counter-clockwise).
+	Rotate270
+)
+
+// add returns the rotation by r followed by o.
+func (r KeyRotation) add(o KeyRotation) KeyRotation {
+	return (r + o) % 4
+}
+
+// apply returns img rotated by the KeyRotation.
+func (r KeyRotation) apply(img *image.RGBA) *image.RGBA {
+	var filter gift.Filter
+	switch r {
+	case Rotate90:
+		filter = gift.Rotate270()
+	case Rotate180:
+		filter = gift.Rotate180()
+	case Rotate270:
+		filter = gift.Rotate90()
+	default:
+		return img
+	}
+
+	g := gift.New(filter)
+	res := image.NewRGBA(g.Bounds(img.Bounds()))
+	g.Draw(res, img)
+	return res
+}
+
+// SetKeyRotation sets the rotation applied to the content of all buttons,
+// except the ones exempted with ExemptKeyFromRotation. The rotation only
+// applies to content written afterwards; the button indices are not
+// changed. The default is Rotate0.
+func (sd *StreamDeck) SetKeyRotation(rotation KeyRotation) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.rotation = rotation
+}
+
+// ExemptKeyFromRotation excludes a button from the rotation set with
+// SetKeyRotation, e.g. because it shows images which have already been
+// rotated. The exemption remains valid when the rotation is changed.
+func (sd *StreamDeck) ExemptKeyFromRotation(btnIndex int) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	sd.Lock()
+	defer sd.Unlock()
+	if sd.rotationExempt == nil {
+		sd.rotationExempt = make(map[int]bool)
+	}
+	sd.rotationExempt[btnIndex] = true
+	return nil
+}
+
+// IncludeKeyInRotation reverts ExemptKeyFromRotation for a button.
+func (sd *StreamDeck) IncludeKeyInRotation(btnIndex int) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	sd.Lock()
+	defer sd.Unlock()
+	delete(sd.rotationExempt, btnIndex)
+	return nil
+}
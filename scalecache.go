@@ -0,0 +1,170 @@
+package StreamDeck
+
+import (
+	"container/list"
+	"hash/crc32"
+	"image"
+	"sync"
+)
+
+// DefaultScaleCacheSize is the default amount of scaled images kept by the
+// pre-scale cache.
+const DefaultScaleCacheSize = 32
+
+// ScaleCacheStats contains the statistics of the pre-scale cache.
+type ScaleCacheStats struct {
+	// Hits is the amount of scaling operations served from the cache.
+	Hits uint64
+	// Misses is the amount of scaling operations which had to be computed.
+	Misses uint64
+	// Entries is the amount of scaled images currently cached.
+	Entries int
+	// Size is the maximum amount of cached images.
+	Size int
+}
+
+// scaleKey identifies the result of a scaling operation. The source image is
+// identified by its address and a checksum of its pixels, so that an image
+// modified in place is scaled again.
+type scaleKey struct {
+	src    image.Image
+	bounds image.Rectangle
+	sum    uint32
+	width  int
+	height int
+	mode   ScaleMode
+}
+
+type scaleEntry struct {
+	key scaleKey
+	img image.Image
+}
+
+// scaleCache is a LRU cache of scaled images. It avoids the expensive
+// resampling when the same image is filled into buttons repeatedly.
+type scaleCache struct {
+	sync.Mutex
+	size    int
+	entries map[scaleKey]*list.Element
+	lru     *list.List
+	hits    uint64
+	misses  uint64
+}
+
+func newScaleCache(size int) *scaleCache {
+	return &scaleCache{
+		size:    size,
+		entries: make(map[scaleKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// scale returns img scaled like scale(), using a cached result if possible.
+func (c *scaleCache) scale(img image.Image, width, height int, mode ScaleMode) image.Image {
+	key, ok := newScaleKey(img, width, height, mode)
+
+	c.Lock()
+	if !ok || c.size <= 0 {
+		c.misses++
+		c.Unlock()
+		return scale(img, width, height, mode)
+	}
+	if el, found := c.entries[key]; found {
+		c.hits++
+		c.lru.MoveToFront(el)
+		res := el.Value.(*scaleEntry).img
+		c.Unlock()
+		return res
+	}
+	c.misses++
+	c.Unlock()
+
+	res := scale(img, width, height, mode)
+
+	c.Lock()
+	defer c.Unlock()
+	if _, found := c.entries[key]; !found && c.size > 0 {
+		c.entries[key] = c.lru.PushFront(&scaleEntry{key: key, img: res})
+		c.evict()
+	}
+	return res
+}
+
+// setSize changes the maximum amount of cached images. A size of zero
+// disables the cache.
+func (c *scaleCache) setSize(size int) {
+	c.Lock()
+	defer c.Unlock()
+	c.size = size
+	c.evict()
+}
+
+// evict removes the least recently used entries exceeding the size. The
+// lock must be held by the caller.
+func (c *scaleCache) evict() {
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*scaleEntry).key)
+	}
+}
+
+func (c *scaleCache) stats() ScaleCacheStats {
+	c.Lock()
+	defer c.Unlock()
+	return ScaleCacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.lru.Len(),
+		Size:    c.size,
+	}
+}
+
+// newScaleKey returns the cache key of a scaling operation. Only images
+// whose pixels can be checksummed efficiently are cached.
+func newScaleKey(img image.Image, width, height int, mode ScaleMode) (scaleKey, bool) {
+	var sum uint32
+	switch src := img.(type) {
+	case *image.RGBA:
+		sum = crc32.ChecksumIEEE(src.Pix)
+	case *image.NRGBA:
+		sum = crc32.ChecksumIEEE(src.Pix)
+	case *image.Gray:
+		sum = crc32.ChecksumIEEE(src.Pix)
+	case *image.YCbCr:
+		sum = crc32.ChecksumIEEE(src.Y)
+		sum = crc32.Update(sum, crc32.IEEETable, src.Cb)
+		sum = crc32.Update(sum, crc32.IEEETable, src.Cr)
+	default:
+		return scaleKey{}, false
+	}
+	return scaleKey{
+		src:    img,
+		bounds: img.Bounds(),
+		sum:    sum,
+		width:  width,
+		height: height,
+		mode:   mode,
+	}, true
+}
+
+// scale scales an image using the pre-scale cache.
+func (sd *StreamDeck) scale(img image.Image, width, height int, mode ScaleMode) image.Image {
+	return sd.scaleCache.scale(img, width, height, mode)
+}
+
+// SetScaleCacheSize sets the maximum amount of scaled images kept by the
+// pre-scale cache. When an image which doesn't match the size of a button
+// is filled repeatedly, the cache skips the expensive resampling. A size of
+// zero disables the cache. The default is DefaultScaleCacheSize.
+func (sd *StreamDeck) SetScaleCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	sd.scaleCache.setSize(size)
+}
+
+// ScaleCacheStats returns the statistics of the pre-scale cache.
+func (sd *StreamDeck) ScaleCacheStats() ScaleCacheStats {
+	return sd.scaleCache.stats()
+}
@@ -0,0 +1,158 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"time"
+)
+
+// Screensaver configures the screensaver of the panel, which dims and
+// optionally blanks the buttons after a period without button events.
+type Screensaver struct {
+	// Idle is the duration without button events after which the
+	// screensaver starts. Zero disables the screensaver.
+	Idle time.Duration
+	// Brightness is the brightness of the panel (in percent) while the
+	// screensaver is active.
+	Brightness int
+	// Blank turns all buttons black while the screensaver is active.
+	Blank bool
+	// SwallowWakePress prevents the button press which wakes the panel
+	// (and its release) from being dispatched, so that the application
+	// doesn't act on a tap which was only meant to wake the panel.
+	SwallowWakePress bool
+}
+
+// SetScreensaver configures the screensaver. Once the panel has been idle
+// for the configured duration, the brightness is reduced and the buttons
+// are blanked (if enabled). The next button event wakes the panel: the
+// brightness set with SetBrightness is restored and the buttons show their
+// content again, before the event is dispatched. Serve must be running to
+// detect button events. Content written to the buttons while the
+// screensaver is active is shown immediately, but doesn't wake the panel.
+func (sd *StreamDeck) SetScreensaver(s Screensaver) error {
+	if s.Brightness < 0 || s.Brightness > 100 {
+		return fmt.Errorf("invalid screensaver brightness %d%%", s.Brightness)
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	if sd.screensaverActive {
+		if err := sd.wake(); err != nil {
+			return err
+		}
+	}
+	sd.screensaver = s
+	sd.armScreensaver()
+	return nil
+}
+
+// screensaverActivity is called with the events of an input report before
+// they are dispatched. It wakes the panel if necessary, removes the
+// swallowed events and restarts the idle timer.
+func (sd *StreamDeck) screensaverActivity(events []Event) []Event {
+	if len(events) == 0 {
+		return events
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	if sd.screensaver.Idle <= 0 {
+		return events
+	}
+
+	swallowPress := false
+	if sd.screensaverActive {
+		if err := sd.wake(); err != nil {
+			sd.log.Warnf("unable to wake panel from screensaver: %v", err)
+		}
+		swallowPress = sd.screensaver.SwallowWakePress
+	}
+
+	res := events[:0]
+	for _, ev := range events {
+		switch {
+		case swallowPress && ev.State == BtnPressed:
+			swallowPress = false
+			sd.swallowedBtn = ev.BtnIndex
+			sd.swallowing = true
+		case sd.swallowing && ev.BtnIndex == sd.swallowedBtn && ev.State == BtnReleased:
+			sd.swallowing = false
+		default:
+			res = append(res, ev)
+		}
+	}
+
+	sd.armScreensaver()
+	return res
+}
+
+// armScreensaver (re)starts the idle timer of the screensaver. The write
+// lock must be held by the caller.
+func (sd *StreamDeck) armScreensaver() {
+	if sd.screensaverTimer != nil {
+		sd.screensaverTimer.Stop()
+		sd.screensaverTimer = nil
+	}
+	// invalidate a timer which has already fired but not yet acquired
+	// the lock
+	sd.screensaverGen++
+	if sd.screensaver.Idle <= 0 {
+		return
+	}
+
+	gen := sd.screensaverGen
+	sd.screensaverTimer = time.AfterFunc(sd.screensaver.Idle, func() {
+		sd.activateScreensaver(gen)
+	})
+}
+
+// stopScreensaver stops the idle timer. The write lock must be held by the
+// caller.
+func (sd *StreamDeck) stopScreensaver() {
+	if sd.screensaverTimer != nil {
+		sd.screensaverTimer.Stop()
+		sd.screensaverTimer = nil
+	}
+	sd.screensaverGen++
+}
+
+// activateScreensaver dims and blanks the panel, unless the timer with the
+// generation gen has been stopped in the meantime.
+func (sd *StreamDeck) activateScreensaver(gen int) {
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	if gen != sd.screensaverGen || sd.screensaverActive {
+		return
+	}
+	sd.screensaverActive = true
+
+	if err := sd.sendBrightness(sd.screensaver.Brightness); err != nil {
+		sd.log.Warnf("unable to dim panel for screensaver: %v", err)
+	}
+	if sd.screensaver.Blank {
+		black := sd.blankBtnBuf()
+		for i := range sd.cache {
+			if err := sd.sendBtnBuf(i, black); err != nil {
+				sd.log.Warnf("unable to blank panel for screensaver: %v", err)
+				break
+			}
+		}
+	}
+}
+
+// wake restores the brightness and the content of the buttons after the
+// screensaver. The write lock must be held by the caller, so that no other
+// write interleaves with the wake sequence.
+func (sd *StreamDeck) wake() error {
+	sd.screensaverActive = false
+	if err := sd.sendBrightness(sd.brightness); err != nil {
+		return err
+	}
+	if sd.screensaver.Blank {
+		return sd.restoreBtns()
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package StreamDeck
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// scrollTextFrameInterval is the time between two frames of ScrollText.
+const scrollTextFrameInterval = 40 * time.Millisecond
+
+// scrollTextMargin is the distance (in pixel) between the text scrolled by
+// ScrollText and the top and bottom border of the panel.
+const scrollTextMargin = 8
+
+// ScrollText scrolls white text on black background horizontally across the
+// whole panel, treating all buttons as one strip. The text enters on the
+// right and moves to the left with speed pixels per second until it has
+// left the panel. If font is nil, the embedded default font is used (see
+// FillText). The text is sized to fill the height of the panel.
+//
+// The spacers between the buttons are handled according to the
+// PanelGapMode: with PanelGapIncluded the text moves continuously behind
+// the physical gaps, with PanelGapInserted no column of the text is hidden
+// by them. ScrollText blocks until the text has passed or the context is
+// done, in which case the error of the context is returned.
+func (sd *StreamDeck) ScrollText(ctx context.Context, text string, f *truetype.Font, speed int) error {
+	if speed <= 0 {
+		return fmt.Errorf("scroll speed must be positive")
+	}
+	if f == nil {
+		var err error
+		f, err = loadDefaultFont()
+		if err != nil {
+			return err
+		}
+	}
+
+	sd.Lock()
+	gapMode := sd.panelGapMode
+	sd.Unlock()
+
+	width, height := sd.panelSize(gapMode)
+
+	strip := renderTextStrip(text, f, height)
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	fb := sd.NewFramebuffer()
+
+	// the text travels from right outside of the canvas to left outside
+	// of it
+	distance := width + strip.Bounds().Dx()
+
+	ticker := time.NewTicker(scrollTextFrameInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		offset := int(time.Since(start).Seconds() * float64(speed))
+		if offset > distance {
+			offset = distance
+		}
+
+		draw.Draw(canvas, canvas.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+		x := width - offset
+		draw.Draw(canvas, strip.Bounds().Add(image.Pt(x, 0)), strip, image.Point{0, 0}, draw.Src)
+
+		var frame image.Image = canvas
+		if gapMode == PanelGapInserted {
+			frame = sd.insertGaps(canvas)
+		}
+		draw.Draw(fb, fb.Bounds(), frame, image.Point{0, 0}, draw.Src)
+		if err := fb.Present(); err != nil {
+			return err
+		}
+
+		if offset == distance {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// renderTextStrip renders a line of white text on black background into an
+// image with the given height and the width of the text.
+func renderTextStrip(text string, f *truetype.Font, height int) *image.RGBA {
+	avail := height - 2*scrollTextMargin
+
+	// the font size is chosen so that the line height equals the
+	// available height
+	face := truetype.NewFace(f, &truetype.Options{Size: float64(avail), DPI: 72})
+	if h := face.Metrics().Height.Ceil(); h > avail {
+		face = truetype.NewFace(f, &truetype.Options{
+			Size: float64(avail) * float64(avail) / float64(h),
+			DPI:  72,
+		})
+	}
+
+	metrics := face.Metrics()
+	width := font.MeasureString(face, text).Ceil()
+	strip := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(strip, strip.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+
+	d := &font.Drawer{
+		Dst:  strip,
+		Src:  image.White,
+		Face: face,
+		Dot:  fixed.P(0, (height-metrics.Height.Ceil())/2+metrics.Ascent.Ceil()),
+	}
+	d.DrawString(text)
+	return strip
+}
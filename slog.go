@@ -0,0 +1,57 @@
+//go:build go1.21
+// +build go1.21
+
+package StreamDeck
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// SlogLogger adapts a structured slog.Logger to the Logger interface. The
+// levels map directly to the slog levels; the arguments of the printf style
+// methods are formatted into the message.
+type SlogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns a Logger which writes to l. If l is nil, the default
+// slog logger is used.
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogLogger{l: l}
+}
+
+func (s *SlogLogger) Debug(args ...interface{}) {
+	s.l.Debug(fmt.Sprint(args...))
+}
+
+func (s *SlogLogger) Debugf(format string, args ...interface{}) {
+	s.l.Debug(fmt.Sprintf(format, args...))
+}
+
+func (s *SlogLogger) Info(args ...interface{}) {
+	s.l.Info(fmt.Sprint(args...))
+}
+
+func (s *SlogLogger) Infof(format string, args ...interface{}) {
+	s.l.Info(fmt.Sprintf(format, args...))
+}
+
+func (s *SlogLogger) Warn(args ...interface{}) {
+	s.l.Warn(fmt.Sprint(args...))
+}
+
+func (s *SlogLogger) Warnf(format string, args ...interface{}) {
+	s.l.Warn(fmt.Sprintf(format, args...))
+}
+
+func (s *SlogLogger) Error(args ...interface{}) {
+	s.l.Error(fmt.Sprint(args...))
+}
+
+func (s *SlogLogger) Errorf(format string, args ...interface{}) {
+	s.l.Error(fmt.Sprintf(format, args...))
+}
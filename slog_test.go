@@ -0,0 +1,58 @@
+//go:build go1.21
+// +build go1.21
+
+package StreamDeck
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// recordHandler is a slog.Handler which keeps the last record.
+type recordHandler struct {
+	record slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.record = r
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordHandler) WithGroup(string) slog.Handler { return h }
+
+func TestSlogLoggerLevels(t *testing.T) {
+	h := &recordHandler{}
+	l := NewSlogLogger(slog.New(h))
+
+	tests := []struct {
+		name  string
+		log   func()
+		level slog.Level
+	}{
+		{"Debug", func() { l.Debug("key ", 1) }, slog.LevelDebug},
+		{"Debugf", func() { l.Debugf("key %d", 1) }, slog.LevelDebug},
+		{"Info", func() { l.Info("key ", 1) }, slog.LevelInfo},
+		{"Infof", func() { l.Infof("key %d", 1) }, slog.LevelInfo},
+		{"Warn", func() { l.Warn("key ", 1) }, slog.LevelWarn},
+		{"Warnf", func() { l.Warnf("key %d", 1) }, slog.LevelWarn},
+		{"Error", func() { l.Error("key ", 1) }, slog.LevelError},
+		{"Errorf", func() { l.Errorf("key %d", 1) }, slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.record = slog.Record{}
+			tt.log()
+			if h.record.Level != tt.level {
+				t.Errorf("level = %v, want %v", h.record.Level, tt.level)
+			}
+			if h.record.Message != "key 1" {
+				t.Errorf("message = %q, want %q", h.record.Message, "key 1")
+			}
+		})
+	}
+}
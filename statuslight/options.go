@@ -0,0 +1,40 @@
+package statuslight
+
+import (
+	"image/color"
+	"time"
+)
+
+// Label is a functional option which sets the label (max 5 characters). By
+// default a short name of the status is shown, e.g. "WARN".
+func Label(text string) func(*StatusLight) {
+	return func(l *StatusLight) {
+		l.text = text
+	}
+}
+
+// StatusColor is a functional option which sets the color of a status.
+func StatusColor(status Status, c color.Color) func(*StatusLight) {
+	return func(l *StatusLight) {
+		l.colors[status] = c
+	}
+}
+
+// Blink is a functional option which sets the states which blink. By
+// default only Critical blinks; without arguments, no status blinks.
+func Blink(states ...Status) func(*StatusLight) {
+	return func(l *StatusLight) {
+		l.blink = make(map[Status]bool, len(states))
+		for _, status := range states {
+			l.blink[status] = true
+		}
+	}
+}
+
+// BlinkInterval is a functional option which sets the time between two
+// toggles of a blinking StatusLight. The default is 500ms.
+func BlinkInterval(interval time.Duration) func(*StatusLight) {
+	return func(l *StatusLight) {
+		l.blinkInterval = interval
+	}
+}
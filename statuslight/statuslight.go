@@ -0,0 +1,204 @@
+package statuslight
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+
+	sd "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/label"
+)
+
+// Status is the semantic state shown by a StatusLight.
+type Status int
+
+const (
+	// Unknown indicates that the state couldn't be determined (yet).
+	Unknown Status = iota
+	// OK indicates that everything works as expected.
+	OK
+	// Warning indicates a problem which needs attention.
+	Warning
+	// Critical indicates a serious problem. It blinks by default.
+	Critical
+)
+
+func (s Status) String() string {
+	switch s {
+	case Unknown:
+		return "Unknown"
+	case OK:
+		return "OK"
+	case Warning:
+		return "Warning"
+	case Critical:
+		return "Critical"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// defaultTexts are the labels shown if no label has been set.
+var defaultTexts = map[Status]string{
+	Unknown:  "?",
+	OK:       "OK",
+	Warning:  "WARN",
+	Critical: "CRIT",
+}
+
+// StatusLight is a "traffic light" key showing a semantic Status as
+// background color with a label. The colors, the label and which states
+// blink can be modified with functional options. The text color is chosen
+// to contrast with the background.
+type StatusLight struct {
+	sync.Mutex
+	streamDeck    *sd.StreamDeck
+	id            int
+	label         *label.Label
+	text          string
+	status        Status
+	colors        map[Status]color.Color
+	blink         map[Status]bool
+	blinkInterval time.Duration
+	blinkOn       bool
+	stopBlink     chan struct{}
+}
+
+// NewStatusLight is the constructor of a StatusLight. The initial status is
+// Unknown. Functional arguments can be supplied to modify its default
+// characteristics.
+func NewStatusLight(sd *sd.StreamDeck, btnIndex int, options ...func(*StatusLight)) (*StatusLight, error) {
+	if sd == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+	if btnIndex < 0 || btnIndex >= sd.Capabilities().NumKeys {
+		return nil, fmt.Errorf("invalid key index %d", btnIndex)
+	}
+
+	l := &StatusLight{
+		streamDeck: sd,
+		id:         btnIndex,
+		status:     Unknown,
+		colors: map[Status]color.Color{
+			Unknown:  color.RGBA{100, 100, 100, 255},
+			OK:       color.RGBA{0, 170, 0, 255},
+			Warning:  color.RGBA{255, 170, 0, 255},
+			Critical: color.RGBA{220, 0, 0, 255},
+		},
+		blink:         map[Status]bool{Critical: true},
+		blinkInterval: 500 * time.Millisecond,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	if len(l.text) > 5 {
+		return nil, fmt.Errorf("label contains more than 5 characters")
+	}
+	if l.blinkInterval <= 0 {
+		return nil, fmt.Errorf("blink interval must be positive")
+	}
+
+	lbl, err := label.NewLabel(sd, btnIndex)
+	if err != nil {
+		return nil, err
+	}
+	l.label = lbl
+
+	return l, nil
+}
+
+// Status returns the current status.
+func (l *StatusLight) Status() Status {
+	l.Lock()
+	defer l.Unlock()
+	return l.status
+}
+
+// SetStatus sets the status and renders the StatusLight. If the status
+// blinks, the key alternates between the color of the status and black
+// until another status is set or Close is called.
+func (l *StatusLight) SetStatus(status Status) error {
+	l.Lock()
+	defer l.Unlock()
+
+	l.stop()
+	l.status = status
+	l.blinkOn = true
+	if err := l.draw(); err != nil {
+		return err
+	}
+
+	if l.blink[status] {
+		l.stopBlink = make(chan struct{})
+		go l.blinkLoop(l.stopBlink)
+	}
+	return nil
+}
+
+// Draw renders the StatusLight on the designated button.
+func (l *StatusLight) Draw() error {
+	l.Lock()
+	defer l.Unlock()
+	return l.draw()
+}
+
+// Close stops blinking.
+func (l *StatusLight) Close() {
+	l.Lock()
+	defer l.Unlock()
+	l.stop()
+}
+
+// stop ends the blinking. The lock must be held by the caller.
+func (l *StatusLight) stop() {
+	if l.stopBlink != nil {
+		close(l.stopBlink)
+		l.stopBlink = nil
+	}
+}
+
+// blinkLoop toggles the key until stop is closed.
+func (l *StatusLight) blinkLoop(stop chan struct{}) {
+	ticker := time.NewTicker(l.blinkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		l.Lock()
+		select {
+		case <-stop:
+			// the status has changed while waiting for the lock
+			l.Unlock()
+			return
+		default:
+		}
+		l.blinkOn = !l.blinkOn
+		if err := l.draw(); err != nil {
+			l.streamDeck.Log().Warn(err.Error())
+		}
+		l.Unlock()
+	}
+}
+
+// draw renders the key. The lock must be held by the caller.
+func (l *StatusLight) draw() error {
+	text := l.text
+	if text == "" {
+		text = defaultTexts[l.status]
+	}
+	bg := l.colors[l.status]
+	if bg == nil || !l.blinkOn {
+		bg = color.Black
+	}
+
+	l.label.SetText(text)
+	l.label.SetBgColor(image.NewUniform(bg))
+	return l.label.Draw()
+}
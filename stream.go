@@ -0,0 +1,73 @@
+package StreamDeck
+
+import (
+	"context"
+	"image"
+	"time"
+)
+
+// streamFrameInterval limits the frame rate of StreamToKey.
+const streamFrameInterval = 40 * time.Millisecond
+
+// StreamToKey shows the frames received from a channel on a button, e.g.
+// from a webcam or a region of the screen. Frames are handled like in
+// FillImage. At most 25 frames per second are written; if frames arrive
+// faster or writing can't keep up, only the latest frame is shown and the
+// stale ones are dropped, so frames never queue up. StreamToKey returns nil
+// when frames is closed, the error of ctx when it is cancelled, or the
+// error of a failed write.
+func (sd *StreamDeck) StreamToKey(ctx context.Context, btnIndex int, frames <-chan image.Image) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+
+	var last time.Time
+	for {
+		var frame image.Image
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case f, ok := <-frames:
+			if !ok {
+				return nil
+			}
+			frame = f
+		}
+
+		if wait := last.Add(streamFrameInterval).Sub(time.Now()); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		frame, closed := latestFrame(frame, frames)
+		if frame != nil {
+			last = time.Now()
+			if err := sd.FillImage(btnIndex, frame); err != nil {
+				return err
+			}
+		}
+		if closed {
+			return nil
+		}
+	}
+}
+
+// latestFrame drains the frames which are pending in the channel and
+// returns the most recent one, or frame if none is pending. closed is true
+// if the channel has been closed.
+func latestFrame(frame image.Image, frames <-chan image.Image) (latest image.Image, closed bool) {
+	for {
+		select {
+		case f, ok := <-frames:
+			if !ok {
+				return frame, true
+			}
+			frame = f
+		default:
+			return frame, false
+		}
+	}
+}
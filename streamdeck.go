@@ -3,15 +3,21 @@
 package StreamDeck
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"image"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/disintegration/gift"
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
 
 	"image/color"
 	"image/draw"
@@ -28,10 +34,17 @@ const VendorID = 0x0fd9
 // ProductID is the USB ProductID assigned to Elgato's Stream Deck
 const ProductID = 0x0060
 
+// ProductIDXL is the USB ProductID assigned to the Stream Deck XL
+const ProductIDXL = 0x006c
+
+// ProductIDMini is the USB ProductID assigned to the Stream Deck Mini
+const ProductIDMini = 0x0063
+
 // Stream Deck output endpoint buffer size
 const OutEndpointBufferSize = 17
 
-// NumButtons is the total amount of Buttons located on the Stream Deck.
+// NumButtons is the total amount of Buttons located on the original Stream
+// Deck. Use Capabilities for the connected model.
 const NumButtons = 15
 
 // numFirstMsgPixels is the amount of pixels which have to be sent to the
@@ -42,7 +55,9 @@ const numFirstMsgPixels = 2583
 // Stream Deck in the second message.
 const numSecondMsgPixels = 2601
 
-// ButtonSize is the size of a button (in pixel).
+// ButtonSize is the size of a button (in pixel) of the original Stream
+// Deck. The images drawn by the library itself (e.g. by FillText) have this
+// size and are scaled to the key size of other models.
 const ButtonSize = 72
 
 // NumButtonColumns is the number of columns on the Stream Deck.
@@ -61,7 +76,10 @@ const PanelWidth = NumButtonColumns*ButtonSize + Spacer*(NumButtonColumns-1)
 const PanelHeight = NumButtonRows*ButtonSize + Spacer*(NumButtonRows-1)
 
 // BtnEvent is a callback which gets executed when the state of a button changes,
-// so whenever it get's pressed or released.
+// so whenever it get's pressed or released. The btnIndex uses the same
+// numbering as FillImage and the other methods writing to a button, so an
+// event for btnIndex always refers to the button FillImage(btnIndex, ...)
+// draws on.
 type BtnEvent func(btnIndex int, newBtnState BtnState)
 
 // BtnState is a type representing the button state.
@@ -74,18 +92,169 @@ const (
 	BtnReleased
 )
 
+// BtnMode determines how a widget derives its on/off state from the events
+// of its button.
+type BtnMode int
+
+const (
+	// Toggle flips the state of the widget on each press.
+	Toggle BtnMode = iota
+	// Momentary turns the widget on while the button is held.
+	Momentary
+)
+
+// NextState returns the new on/off state of a widget with the current state
+// on after a button event.
+func (m BtnMode) NextState(on bool, state BtnState) bool {
+	switch m {
+	case Momentary:
+		return state == BtnPressed
+	default:
+		if state == BtnPressed {
+			return !on
+		}
+		return on
+	}
+}
+
+// ScaleMode determines how images which don't match the size of a button
+// are scaled.
+type ScaleMode int
+
+const (
+	// ScaleStretch stretches the image to the size of the button, ignoring
+	// its aspect ratio.
+	ScaleStretch ScaleMode = iota
+	// ScaleFit scales the image to fit into the button while keeping its
+	// aspect ratio. The remaining area is filled with the background color.
+	ScaleFit
+	// ScaleFill scales the image to cover the whole button while keeping its
+	// aspect ratio. The image is center-cropped if necessary.
+	ScaleFill
+)
+
+// PanelGapMode determines how FillPanel treats the spacers between the
+// buttons.
+type PanelGapMode int
+
+const (
+	// PanelGapIncluded assumes that the image covers the whole panel
+	// including the spacers (PanelWidth x PanelHeight of the Capabilities).
+	// The parts of the image located behind the spacers are not shown, so
+	// that the image looks continuous across the physical gaps.
+	PanelGapIncluded PanelGapMode = iota
+	// PanelGapInserted assumes that the image consists of the buttons placed
+	// directly next to each other (columns*KeySize x rows*KeySize of the
+	// Capabilities of the model). The library inserts the spacing, so that
+	// no part of the image is lost.
+	PanelGapInserted
+)
+
+// TextErrorMode determines how WriteText handles lines which can not be
+// rendered.
+type TextErrorMode int
+
+const (
+	// TextStrict aborts WriteText on the first line which can not be
+	// rendered. Nothing is sent to the button.
+	TextStrict TextErrorMode = iota
+	// TextBestEffort renders all remaining lines and sends the partially
+	// rendered image to the button. The errors of all failed lines are
+	// returned combined.
+	TextBestEffort
+)
+
+// ReconnectPolicy decides whether Serve continues after reading from or
+// connecting to the Stream Deck failed. attempt counts the consecutive
+// failures, starting at 1. If retry is true, Serve waits for delay and then
+// tries to reconnect; otherwise Serve returns err.
+type ReconnectPolicy func(attempt int, err error) (retry bool, delay time.Duration)
+
+// DefaultReconnectPolicy retries forever, waiting DefaultReconnectionTime
+// between the attempts.
+func DefaultReconnectPolicy(attempt int, err error) (bool, time.Duration) {
+	return true, DefaultReconnectionTime
+}
+
 // ReadErrorCb is a callback which gets executed in case reading from the
 // Stream Deck fails (e.g. the cable get's disconnected).
 type ReadErrorCb func(err error)
 
-// StreamDeck is the object representing the Elgato Stream Deck.
+// StreamDeck is the object representing the Elgato Stream Deck. The
+// embedded Mutex guards the configuration and the button states; writes to
+// the device, the button cache and the animations are guarded by writeMu.
+// Both locks are never held while a callback executes, so callbacks can
+// always draw on the panel.
 type StreamDeck struct {
 	sync.Mutex
-	device            *USBDevice
-	btnEventCb        BtnEvent
-	btnState          []BtnState
-	log               Logger
-	onConnectCallback func()
+	writeMu               sync.Mutex
+	device                Device
+	model                 Model
+	btnEventCb            BtnEvent
+	btnState              []BtnState
+	log                   Logger
+	onConnectCallback     func()
+	clearOnClose          bool
+	resetOnClose          bool
+	invertedInput         bool
+	background            color.Color
+	scaleMode             ScaleMode
+	readBufferSize        int
+	onReadyCallback       func()
+	ready                 bool
+	panelGapMode          PanelGapMode
+	actions               map[string]func()
+	bindings              map[int]string
+	cache                 []btnCache
+	subscribers           map[chan Event]struct{}
+	supersampling         int
+	animations            map[int]*animation
+	syncDispatch          bool
+	badgeBase             map[int]*image.RGBA
+	textErrorMode         TextErrorMode
+	reconnectLog          logThrottle
+	reconnectPolicy       ReconnectPolicy
+	keyMask               KeyMask
+	serving               int32
+	eventThrottle         time.Duration
+	debounce              time.Duration
+	lastEvent             []time.Time
+	overlays              map[int][]*overlay
+	resetGestureCancel    func()
+	scaleCache            *scaleCache
+	gamma                 *gammaLUT
+	onResumeCallback      func()
+	resumeWindow          time.Duration
+	serial                string
+	disconnectedAt        time.Time
+	errorImage            image.Image
+	pressCounts           []int
+	clearColor            color.Color
+	brightness            int
+	screensaver           Screensaver
+	screensaverTimer      *time.Timer
+	screensaverGen        int
+	screensaverActive     bool
+	swallowedBtn          int
+	swallowing            bool
+	onAllReleasedCallback func()
+	rotation              KeyRotation
+	rotationExempt        map[int]bool
+	templates             map[int]keyTemplate
+	bootAnim              BootAnim
+	inputStats            InputStats
+	confirmCancels        map[int]func()
+	keyImages             map[int]keyImages
+	longPressCb           func(btnIndex int)
+	longPressDuration     time.Duration
+	longPressTimers       map[int]*time.Timer
+	multiTapCb            func(btnIndex, taps int)
+	multiTapWindow        time.Duration
+	multiTaps             map[int]*multiTap
+	debounceStates        []debounceState
+	// statesLen is the amount of button states of the last input report;
+	// a mismatch with the model is only logged when it changes
+	statesLen int
 }
 
 // TextButton holds the lines to be written to a button and the desired
@@ -103,10 +272,25 @@ type TextLine struct {
 	Font      *truetype.Font
 	FontSize  float64
 	FontColor color.Color
+	// Vertical rotates the line by 90°, so that it reads from bottom to
+	// top. PosX and PosY refer to the rotated button: PosX is the distance
+	// from the bottom border, PosY the distance from the left border.
+	Vertical bool
+	// TopToBottom turns a vertical line the other way round, so that it
+	// reads from top to bottom. PosX is then the distance from the top
+	// border and PosY the distance from the right border.
+	TopToBottom bool
+	// Hinting sets how the glyph outlines are fitted to the pixel grid.
+	// font.HintingFull yields crisper small text. The default is
+	// font.HintingNone.
+	Hinting font.Hinting
 }
 
 // Page contains the configuration of one particular page of buttons. Pages
-// can be nested to an arbitrary depth.
+// can be nested to an arbitrary depth. The PageManager identifies pages
+// with ==, so implementations must be comparable types; implement Page
+// with pointer receivers, since comparing structs containing slices or maps
+// panics.
 type Page interface {
 	Set(btnIndex int, state BtnState) Page
 	Parent() Page
@@ -116,36 +300,110 @@ type Page interface {
 
 // NewStreamDeck is the constructor of the StreamDeck object. If several StreamDecks
 // are connected to this PC, the Streamdeck can be selected by supplying
-// the optional serial number of the Device. In the examples folder there is
-// a small program which enumerates all available Stream Decks. If no serial number
-// is supplied, the first StreamDeck found will be selected.
+// the optional serial number of the Device or its DeckInfo.ID, which also
+// selects decks without a unique serial number. ListDevices returns the
+// serial numbers and EnumerateDecks the IDs of all available Stream Decks.
+// If no serial number is supplied,
+// the first StreamDeck found will be selected; the supported models are
+// tried one after another. The device is accessed through libusb, or
+// through the HID stack of the operating system if the package is built
+// with the "hid" build tag.
 func NewStreamDeck(logger Logger, serial ...string) (*StreamDeck, error) {
 	if len(serial) > 1 {
 		return nil, fmt.Errorf("only <= 1 serial numbers must be provided")
 	}
-
-	device := NewUSBDevice(ProductID, VendorID)
 	if len(serial) == 1 {
-		deviceSerialNumber, err := device.GetSerialNumber()
+		return newStreamDeckForDeck(logger, serial[0])
+	}
+
+	var connectErr error
+	for _, m := range models {
+		device := newDefaultDevice(m.ProductID, VendorID)
+		if connectErr = device.Connect(); connectErr != nil {
+			continue
+		}
+		return NewStreamDeckWithDevice(logger, device)
+	}
+
+	return nil, connectErr
+}
+
+// newStreamDeckForDeck connects to the deck found by EnumerateDecks with the
+// given serial number or ID. Since every deck is addressed by its USB path,
+// any of several decks of the same model can be selected. Synthetic IDs
+// always select the deck through libusb, since the HID backend can only
+// tell decks apart by serial number.
+func newStreamDeckForDeck(logger Logger, id string) (*StreamDeck, error) {
+	log := logger
+	if log == nil {
+		log = NewStdLogger()
+	}
+	decks, err := enumerateDecks(log)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deck := range decks {
+		if deck.ID != id && deck.Serial != id {
+			continue
+		}
+		var device Device
+		if strings.HasPrefix(id, syntheticIDPrefix) {
+			device = NewUSBDeviceWithPath(deck.Model.ProductID, VendorID, deck.USBPath)
+		} else {
+			device = newDeckDevice(deck)
+		}
+		sd, err := NewStreamDeckWithDevice(logger, device)
 		if err != nil {
+			device.Close()
 			return nil, err
 		}
+		return sd, nil
+	}
 
-		if deviceSerialNumber != serial[0] {
-			return nil, fmt.Errorf("no stream deck device found with serial number %s", serial[0])
-		}
+	return nil, fmt.Errorf("no stream deck device found with serial number %s", id)
+}
+
+// NewStreamDeckWithDevice is the constructor of the StreamDeck object for
+// an arbitrary Device, like the VirtualDevice. The device is connected if
+// necessary.
+func NewStreamDeckWithDevice(logger Logger, device Device) (*StreamDeck, error) {
+	if device == nil {
+		return nil, fmt.Errorf("device must not be nil")
 	}
 
-	err := device.Connect()
+	model, err := modelForProductID(device.GetProductID())
 	if err != nil {
 		return nil, err
 	}
 
+	if !device.IsConnected() {
+		if err := device.Connect(); err != nil {
+			return nil, err
+		}
+	}
+
 	sd := &StreamDeck{
-		device:   device,
-		btnState: make([]BtnState, NumButtons),
-		log:      logger,
+		device:          device,
+		model:           model,
+		btnState:        make([]BtnState, model.NumButtons),
+		log:             logger,
+		clearOnClose:    true,
+		background:      color.Black,
+		clearColor:      color.Black,
+		brightness:      100,
+		readBufferSize:  model.InputReportSize,
+		actions:         make(map[string]func()),
+		bindings:        make(map[int]string),
+		cache:           make([]btnCache, model.NumButtons),
+		supersampling:   1,
+		reconnectLog:    logThrottle{interval: DefaultReconnectLogInterval},
+		reconnectPolicy: DefaultReconnectPolicy,
+		scaleCache:      newScaleCache(DefaultScaleCacheSize),
+		resumeWindow:    DefaultResumeWindow,
+		statesLen:       model.NumButtons,
 	}
+	sd.serial, _ = device.GetSerialNumber()
 
 	if logger == nil {
 		sd.log = NewStdLogger()
@@ -157,66 +415,396 @@ func NewStreamDeck(logger Logger, serial ...string) (*StreamDeck, error) {
 	}
 
 	sd.ClearAllBtns()
+	sd.setReady(true)
 
 	return sd, nil
 }
 
+// OnAllReleased sets a callback which gets executed by Serve whenever the
+// last pressed button is released, i.e. when the user stops interacting
+// with the panel. It is executed after the events of the release, in the
+// same way as the BtnEvent callback (see SetSyncDispatch).
+func (sd *StreamDeck) OnAllReleased(callback func()) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.onAllReleasedCallback = callback
+}
+
 func (sd *StreamDeck) OnConnect(callback func()) {
 	sd.onConnectCallback = callback
 }
 
+// OnReady sets a callback which gets executed once the panel is in a known
+// state, i.e. after the buttons have been cleared during initialization or
+// after a reconnect. Unlike OnConnect, which fires when the USB link is
+// established, OnReady can be used to start rendering without racing the
+// library's own clearing. If the panel is already ready, the callback is
+// executed immediately.
+func (sd *StreamDeck) OnReady(callback func()) {
+	sd.Lock()
+	sd.onReadyCallback = callback
+	ready := sd.ready
+	sd.Unlock()
+
+	if ready && callback != nil {
+		callback()
+	}
+}
+
+// setReady updates the ready state of the panel and executes the OnReady
+// callback when the panel becomes ready.
+func (sd *StreamDeck) setReady(ready bool) {
+	sd.Lock()
+	sd.ready = ready
+	cb := sd.onReadyCallback
+	sd.Unlock()
+
+	if ready && cb != nil {
+		cb()
+	}
+}
+
+// ErrAlreadyServing is returned by Serve if it is already running.
+var ErrAlreadyServing = errors.New("already serving")
+
+// Serve reads the button events from the Stream Deck and executes the
+// callbacks until stop is signalled. If reading from or connecting to the
+// device fails, the ReconnectPolicy decides whether Serve reconnects or
+// returns the error. Only one Serve may run at a time; a second call returns
+// ErrAlreadyServing. Serve is a wrapper around ServeContext.
 func (sd *StreamDeck) Serve(stop chan bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := sd.ServeContext(ctx)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+// ServeContext reads the button events from the Stream Deck and executes
+// the callbacks like Serve until ctx is done, in which case the error of
+// ctx is returned. If reads of the device can be aborted (ContextReaders
+// like USBDevice, HIDDevice and VirtualDevice), ServeContext only returns
+// after its reading goroutine has terminated; otherwise the goroutine
+// terminates with the next input report or read timeout (see
+// SetReadTimeout).
+func (sd *StreamDeck) ServeContext(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&sd.serving, 0, 1) {
+		return ErrAlreadyServing
+	}
+	defer atomic.StoreInt32(&sd.serving, 0)
+
 	messageChan := make(chan []byte)
 	errorChan := make(chan error)
+	done := make(chan struct{})
+	readCtx, cancelRead := context.WithCancel(ctx)
+	var reader sync.WaitGroup
+	defer func() {
+		close(done)
+		cancelRead()
+		if canCancelRead(sd.device) {
+			reader.Wait()
+		}
+	}()
+
+	reader.Add(1)
 	go func() {
+		defer reader.Done()
+
+		attempt := 0
+		// retry asks the reconnect policy whether to continue after an
+		// error and waits for the requested delay.
+		retry := func(err error) bool {
+			attempt++
+			sd.Lock()
+			policy := sd.reconnectPolicy
+			sd.Unlock()
+
+			ok, delay := policy(attempt, err)
+			if !ok {
+				select {
+				case errorChan <- err:
+				case <-done:
+				}
+				return false
+			}
+			select {
+			case <-time.After(delay):
+				return true
+			case <-done:
+				return false
+			}
+		}
+
 		for {
 			if !sd.device.IsConnected() {
 				if err := sd.device.Connect(); err != nil {
-					sd.log.Warn(err.Error())
-					errorChan <- err
+					sd.logReconnectFailure(err)
+					if retry(err) {
+						continue
+					}
 					return
 				} else {
-					if sd.onConnectCallback != nil {
-						sd.onConnectCallback()
+					attempt = 0
+					sd.logReconnected()
+					if sd.checkResume() {
+						sd.resume()
+					} else {
+						if sd.onConnectCallback != nil {
+							sd.onConnectCallback()
+						}
+						sd.writeMu.Lock()
+						sd.invalidateCache()
+						sd.writeMu.Unlock()
+						sd.ClearAllBtns()
+						sd.playBootAnimation()
+						sd.setReady(true)
 					}
 				}
 			}
 
-			data := make([]byte, OutEndpointBufferSize)
-			_, err := sd.device.read(data)
+			sd.Lock()
+			data := make([]byte, sd.readBufferSize)
+			sd.Unlock()
+			n, err := sd.readReport(readCtx, data)
+			if readCtx.Err() != nil {
+				// ServeContext has returned
+				return
+			}
+			if err == ErrReadTimeout {
+				// no input; check whether Serve has returned meanwhile
+				select {
+				case <-done:
+					return
+				default:
+					continue
+				}
+			}
 			if err != nil {
-				errorChan <- err
+				sd.setReady(false)
+				sd.markDisconnected()
+				if retry(err) {
+					continue
+				}
+				return
+			}
+			attempt = 0
+			select {
+			case messageChan <- data[:n]:
+			case <-done:
 				return
-			} else {
-				messageChan <- data
 			}
 		}
 	}()
 
 	for {
 		select {
-		case <-stop:
-			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		case err := <-errorChan:
 			return err
 		case data := <-messageChan:
-			// strip off the first and end byte
-			data = data[1 : len(data)-1]
-			sd.Lock()
-			// we have to iterate over all 15 buttons and check if the state
-			// has changed. If it has changed, execute the callback.
-			for i, b := range data {
-				if sd.btnState[i] != intToButtonState(int(b)) {
-					sd.btnState[i] = intToButtonState(int(b))
-					if sd.btnEventCb != nil {
-						btnState := sd.btnState[i]
-						go sd.btnEventCb(i, btnState)
-					}
-				}
+			// the callbacks are executed after the button states have been
+			// updated and the lock has been released
+			events, allReleased := sd.updateBtnStates(data)
+			for _, ev := range sd.screensaverActivity(events) {
+				sd.dispatch(ev)
 			}
-			sd.Unlock()
+			if allReleased {
+				sd.dispatchAllReleased()
+			}
+		}
+	}
+}
+
+// readReport reads an input report from the device. The read is aborted
+// when ctx is done if the device is a ContextReader.
+func (sd *StreamDeck) readReport(ctx context.Context, data []byte) (int, error) {
+	if r, ok := sd.device.(ContextReader); ok {
+		return r.ReadContext(ctx, data)
+	}
+	return sd.device.Read(data)
+}
+
+// updateBtnStates updates the button states from an input report and
+// returns an event for every button which changed its state. allReleased
+// is true if the last pressed button has been released.
+func (sd *StreamDeck) updateBtnStates(report []byte) (events []Event, allReleased bool) {
+	// strip off the report header; the position of the button
+	// states depends on the model
+	data := sd.model.buttonStates(report)
+	now := time.Now()
+
+	sd.Lock()
+	defer sd.Unlock()
+
+	sd.inputStats.Reports++
+	if len(data) != sd.statesLen {
+		sd.statesLen = len(data)
+		if len(data) != len(sd.btnState) {
+			// a partially failing device or a model mismatch; only the
+			// buttons contained in the report are updated
+			sd.log.Debugf("input report contains %d button states, expected %d",
+				len(data), len(sd.btnState))
+		}
+	}
+
+	wasPressed := sd.anyPressed()
+	// we have to iterate over all buttons and check if the state
+	// has changed.
+	for pos, b := range data {
+		// use the same numbering for events as for writing images
+		i := sd.model.btnIndex(pos)
+		if i < 0 || i >= len(sd.btnState) {
+			continue
+		}
+		state := intToButtonState(int(b))
+		if sd.invertedInput {
+			state = invertButtonState(state)
+		}
+		if sd.debounced(i, state) {
+			continue
+		}
+		if ev, ok := sd.applyBtnState(i, state, now); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, wasPressed && !sd.anyPressed()
+}
+
+// applyBtnState updates the state of a button and returns the event to be
+// dispatched, if any. The lock must be held by the caller.
+func (sd *StreamDeck) applyBtnState(btnIndex int, state BtnState, now time.Time) (Event, bool) {
+	if sd.btnState[btnIndex] == state {
+		return Event{}, false
+	}
+	sd.btnState[btnIndex] = state
+	if sd.throttled(btnIndex, now) {
+		sd.log.Debugf("event of button %d arrived too fast, dropping it", btnIndex)
+		sd.inputStats.Dropped++
+		return Event{}, false
+	}
+	if state == BtnPressed {
+		sd.countPress(btnIndex)
+	}
+	return Event{BtnIndex: btnIndex, State: state, Time: now}, true
+}
+
+// anyPressed returns true if at least one button is pressed. The lock must
+// be held by the caller.
+func (sd *StreamDeck) anyPressed() bool {
+	for _, state := range sd.btnState {
+		if state == BtnPressed {
+			return true
 		}
 	}
+	return false
+}
+
+// dispatchAllReleased executes the OnAllReleased callback like the BtnEvent
+// callback.
+func (sd *StreamDeck) dispatchAllReleased() {
+	sd.Lock()
+	cb := sd.onAllReleasedCallback
+	synchronous := sd.syncDispatch
+	sd.Unlock()
+
+	if cb == nil {
+		return
+	}
+	if synchronous {
+		cb()
+	} else {
+		go cb()
+	}
+}
+
+// throttled returns true if an event of a button at the point in time now
+// has to be dropped due to the event throttle. Otherwise the time of the
+// event is recorded. The lock must be held by the caller.
+func (sd *StreamDeck) throttled(btnIndex int, now time.Time) bool {
+	if sd.eventThrottle <= 0 {
+		return false
+	}
+	if sd.lastEvent == nil {
+		sd.lastEvent = make([]time.Time, len(sd.btnState))
+	}
+	last := sd.lastEvent[btnIndex]
+	if !last.IsZero() && now.Sub(last) < sd.eventThrottle {
+		return true
+	}
+	sd.lastEvent[btnIndex] = now
+	return false
+}
+
+// dispatch executes the callbacks registered for a button event. The lock
+// must not be held by the caller.
+func (sd *StreamDeck) dispatch(ev Event) {
+	sd.swapKeyImage(ev)
+
+	sd.Lock()
+	sd.inputStats.Dispatched++
+	sd.trackLongPress(ev)
+	sd.countTap(ev)
+	sd.publish(ev)
+	cb := sd.btnEventCb
+	var action func()
+	if ev.State == BtnPressed {
+		action = sd.actions[sd.bindings[ev.BtnIndex]]
+	}
+	synchronous := sd.syncDispatch
+	sd.Unlock()
+
+	run := func(fn func()) {
+		if synchronous {
+			fn()
+		} else {
+			go fn()
+		}
+	}
+
+	if cb != nil {
+		run(func() { cb(ev.BtnIndex, ev.State) })
+	}
+	if action != nil {
+		run(action)
+	}
+}
+
+// IsServing returns true while Serve is running.
+func (sd *StreamDeck) IsServing() bool {
+	return atomic.LoadInt32(&sd.serving) == 1
+}
+
+// USBPath returns the physical USB path (bus and ports) of the Stream Deck.
+// Unlike the serial number, the path identifies the port the device is
+// plugged into, which allows to tell identical devices apart. An empty
+// string is returned if the device doesn't provide a path.
+func (sd *StreamDeck) USBPath() string {
+	if d, ok := sd.device.(interface{ GetUSBPath() string }); ok {
+		return d.GetUSBPath()
+	}
+	return ""
+}
+
+// MaxPacketSize returns the maximum packet size (in bytes) of the endpoint
+// used for writing to the Stream Deck, or 0 if the device doesn't provide
+// it.
+func (sd *StreamDeck) MaxPacketSize() int {
+	if d, ok := sd.device.(interface{ GetMaxPacketSize() int }); ok {
+		return d.GetMaxPacketSize()
+	}
+	return 0
 }
 
 func (sd *StreamDeck) IsConnected() bool {
@@ -226,6 +814,57 @@ func (sd *StreamDeck) IsConnected() bool {
 	return false
 }
 
+// ButtonStates returns a snapshot of the states of all buttons, indexed like
+// the events.
+func (sd *StreamDeck) ButtonStates() []BtnState {
+	sd.Lock()
+	defer sd.Unlock()
+	states := make([]BtnState, len(sd.btnState))
+	copy(states, sd.btnState)
+	return states
+}
+
+// PressedMask returns the currently pressed buttons as a bitmask; bit i is
+// set if button i is pressed. Unlike ButtonStates it doesn't allocate, and
+// combinations of buttons can be compared directly.
+func (sd *StreamDeck) PressedMask() uint32 {
+	sd.Lock()
+	defer sd.Unlock()
+	var mask uint32
+	for i, state := range sd.btnState {
+		if state == BtnPressed && i < 32 {
+			mask |= 1 << uint(i)
+		}
+	}
+	return mask
+}
+
+// Capabilities returns the Capabilities of the connected Stream Deck model.
+func (sd *StreamDeck) Capabilities() Capabilities {
+	return sd.model.capabilities()
+}
+
+// KeyRect returns the position of a key on the panel, i.e. within an image
+// passed to FillPanel with PanelGapIncluded or returned by
+// VirtualDevice.Image.
+func (sd *StreamDeck) KeyRect(btnIndex int) (image.Rectangle, error) {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return image.Rectangle{}, err
+	}
+	return sd.model.btnRect(btnIndex), nil
+}
+
+// Model returns the detected model of the connected Stream Deck, e.g. to
+// distinguish between the original Stream Deck and the XL.
+func (sd *StreamDeck) Model() Model {
+	return sd.model
+}
+
+// Log returns the Logger used by the StreamDeck.
+func (sd *StreamDeck) Log() Logger {
+	return sd.log
+}
+
 // SetBtnEventCb sets the BtnEvent callback which get's executed whenever
 // a Button event (pressed/released) occures.
 func (sd *StreamDeck) SetBtnEventCb(ev BtnEvent) {
@@ -234,29 +873,271 @@ func (sd *StreamDeck) SetBtnEventCb(ev BtnEvent) {
 	sd.btnEventCb = ev
 }
 
+// SetReconnectPolicy sets the policy which decides whether Serve tries to
+// reconnect after an error. The default is DefaultReconnectPolicy. Passing
+// nil restores it.
+func (sd *StreamDeck) SetReconnectPolicy(policy ReconnectPolicy) {
+	if policy == nil {
+		policy = DefaultReconnectPolicy
+	}
+	sd.Lock()
+	defer sd.Unlock()
+	sd.reconnectPolicy = policy
+}
+
+// SetEventThrottle sets the minimum interval between two events of the same
+// button. Events arriving faster are dropped (and logged on debug level),
+// which protects handlers doing expensive work from rapid press/release
+// bursts. Since dropped events are not delivered, handlers may miss a
+// release; ButtonStates and PressedMask always reflect the actual state. An
+// interval of zero (the default) disables the throttle.
+func (sd *StreamDeck) SetEventThrottle(interval time.Duration) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.eventThrottle = interval
+}
+
+// SetSyncDispatch determines if the BtnEvent callback and the bound actions
+// are executed synchronously within Serve. By default every callback runs
+// in its own goroutine. With synchronous dispatch the callbacks are executed
+// one after another in the order of the events, and the next input report is
+// only processed once they have returned. Callbacks may draw on the panel in
+// both modes.
+func (sd *StreamDeck) SetSyncDispatch(synchronous bool) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.syncDispatch = synchronous
+}
+
+// SetTextErrorMode sets how WriteText handles lines which can not be
+// rendered, e.g. because of an unrenderable glyph. The default is
+// TextStrict.
+func (sd *StreamDeck) SetTextErrorMode(mode TextErrorMode) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.textErrorMode = mode
+}
+
+// SetClearOnClose determines if all buttons are cleared when the connection
+// to the Stream Deck is closed. By default the buttons are cleared. Disable it
+// if the panel should keep showing its last content after Close.
+func (sd *StreamDeck) SetClearOnClose(clear bool) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.clearOnClose = clear
+}
+
+// SetInvertedInput flips the interpretation of the button states reported
+// by the device. Some variants report 0 for a pressed and 1 for a released
+// button, opposite to the original Stream Deck.
+func (sd *StreamDeck) SetInvertedInput(inverted bool) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.invertedInput = inverted
+}
+
+// SetClearColor sets the color with which ClearBtn and ClearAllBtns fill
+// the buttons, e.g. when the panel is initialized after a reconnect or
+// cleared on Close. The color is drawn opaque. The default is black.
+func (sd *StreamDeck) SetClearColor(c color.Color) {
+	if c == nil {
+		c = color.Black
+	}
+	sd.Lock()
+	defer sd.Unlock()
+	sd.clearColor = c
+}
+
+// SetBackground sets the color on which transparent images are composited
+// and which fills the padding of letterboxed images (see ScaleFit). The
+// default background is black.
+func (sd *StreamDeck) SetBackground(c color.Color) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.background = c
+}
+
+// SetScaleMode sets how FillImage scales images which don't match the size
+// of a button. The default is ScaleStretch.
+func (sd *StreamDeck) SetScaleMode(mode ScaleMode) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.scaleMode = mode
+}
+
+// SetReadBufferSize sets the size of the buffer used for reading input
+// reports. By default the input report size of the model is used. A larger
+// buffer can be necessary for firmware revisions which send longer reports.
+func (sd *StreamDeck) SetReadBufferSize(size int) error {
+	if size <= 0 {
+		return fmt.Errorf("read buffer size must be positive")
+	}
+	sd.Lock()
+	defer sd.Unlock()
+	sd.readBufferSize = size
+	return nil
+}
+
+// SetReadTimeout sets the maximum duration a read of the device waits for
+// an input report. Without a timeout (zero, the default) the reading
+// goroutine of a device which isn't a ContextReader remains blocked until
+// the next button event after Serve has returned; with a timeout it
+// terminates within the timeout. A timeout is not treated as an error. The
+// device must support read timeouts, like USBDevice, HIDDevice and
+// VirtualDevice.
+func (sd *StreamDeck) SetReadTimeout(timeout time.Duration) error {
+	device := sd.device
+	if rd, ok := device.(*RecordingDevice); ok {
+		// the RecordingDevice forwards the timeout to the wrapped device
+		device = rd.Device
+	}
+	if _, ok := device.(readTimeouter); !ok {
+		return fmt.Errorf("device does not support read timeouts")
+	}
+	sd.device.(readTimeouter).SetReadTimeout(timeout)
+	return nil
+}
+
+// SetPanelGapMode sets how FillPanel treats the spacers between the buttons.
+// The default is PanelGapIncluded.
+func (sd *StreamDeck) SetPanelGapMode(mode PanelGapMode) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.panelGapMode = mode
+}
+
+// SetResetOnClose determines if the Stream Deck is reset (see Reset) when
+// the connection is closed, so that it shows the Elgato logo again instead
+// of black buttons. If enabled, the buttons are reset instead of cleared,
+// regardless of SetClearOnClose. By default it is disabled.
+func (sd *StreamDeck) SetResetOnClose(reset bool) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.resetOnClose = reset
+}
+
 // Close the connection to the Elgato Stream Deck
 func (sd *StreamDeck) Close() error {
-	sd.ClearAllBtns()
+	sd.Lock()
+	clear := sd.clearOnClose
+	reset := sd.resetOnClose
+	sd.stopLongPressTimers()
+	sd.stopMultiTaps()
+	sd.Unlock()
+
+	sd.writeMu.Lock()
+	sd.stopAllAnimations()
+	sd.stopScreensaver()
+	sd.writeMu.Unlock()
+
+	switch {
+	case reset:
+		if err := sd.Reset(); err != nil {
+			sd.log.Warn(err.Error())
+		}
+	case clear:
+		sd.ClearAllBtns()
+	}
 	return sd.device.Close()
 }
 
-// ClearBtn fills a particular key with the color black
+// Reset resets the Stream Deck to its standby screen, which shows the
+// Elgato logo. The content of all buttons is lost; running animations are
+// stopped.
+func (sd *StreamDeck) Reset() error {
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+
+	sd.stopAllAnimations()
+	sd.invalidateCache()
+
+	if sd.model.protocolVersion == 2 {
+		report := make([]byte, 32)
+		copy(report, []byte{'\x03', '\x02'})
+		return sd.device.SendFeatureReport(report)
+	}
+	report := make([]byte, OutEndpointBufferSize)
+	copy(report, []byte{'\x0B', '\x63'})
+	return sd.device.SendFeatureReport(report)
+}
+
+// SendRaw writes a raw output report to the Stream Deck and returns the
+// amount of bytes written. You probably don't need this! It is an escape
+// hatch for reverse engineering the protocol of new models; the report is sent
+// as is without any validation.
+func (sd *StreamDeck) SendRaw(report []byte) (int, error) {
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	return sd.device.Write(report)
+}
+
+// SendRawFeature sends a raw HID feature report to the Stream Deck. The
+// first byte must contain the report ID. You probably don't need this! Like
+// SendRaw it is intended for protocol debugging only.
+func (sd *StreamDeck) SendRawFeature(report []byte) error {
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	return sd.device.SendFeatureReport(report)
+}
+
+// SetBrightness sets the brightness of the panel in percent (0-100). While
+// the screensaver is active, the brightness is applied when the panel
+// wakes up.
+func (sd *StreamDeck) SetBrightness(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid brightness %d%%", percent)
+	}
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	if sd.screensaverActive {
+		sd.brightness = percent
+		return nil
+	}
+	if err := sd.sendBrightness(percent); err != nil {
+		return err
+	}
+	sd.brightness = percent
+	return nil
+}
+
+// sendBrightness sends the brightness (in percent) to the Stream Deck. The
+// write lock must be held by the caller.
+func (sd *StreamDeck) sendBrightness(percent int) error {
+	if sd.model.protocolVersion == 2 {
+		report := make([]byte, 32)
+		copy(report, []byte{'\x03', '\x08', byte(percent)})
+		return sd.device.SendFeatureReport(report)
+	}
+	report := make([]byte, OutEndpointBufferSize)
+	copy(report, []byte{'\x05', '\x55', '\xAA', '\xD1', '\x01', byte(percent)})
+	return sd.device.SendFeatureReport(report)
+}
+
+// ClearBtn fills a particular key with the clear color (black by default,
+// see SetClearColor)
 func (sd *StreamDeck) ClearBtn(btnIndex int) error {
 
-	if err := checkValidKeyIndex(btnIndex); err != nil {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
 		return err
 	}
-	return sd.FillColor(btnIndex, 0, 0, 0)
+
+	sd.Lock()
+	c := color.NRGBAModel.Convert(sd.clearColor).(color.NRGBA)
+	sd.Unlock()
+
+	return sd.FillColor(btnIndex, int(c.R), int(c.G), int(c.B))
 }
 
-// ClearAllBtns fills all keys with the color black
+// ClearAllBtns fills all keys with the clear color
 func (sd *StreamDeck) ClearAllBtns() {
-	for i := 14; i >= 0; i-- {
+	for i := sd.model.NumButtons - 1; i >= 0; i-- {
 		sd.ClearBtn(i)
 	}
 }
 
-// FillColor fills the given button with a solid color.
+// FillColor fills the given button with a solid color. Nothing is sent to
+// the Stream Deck if the button already displays the color.
 func (sd *StreamDeck) FillColor(btnIndex, r, g, b int) error {
 
 	if err := checkRGB(r); err != nil {
@@ -269,50 +1150,58 @@ func (sd *StreamDeck) FillColor(btnIndex, r, g, b int) error {
 		return err
 	}
 
-	img := image.NewRGBA(image.Rect(0, 0, ButtonSize, ButtonSize))
-	rgbaColor := color.RGBA{uint8(r), uint8(g), uint8(b), 0}
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+
+	rgbaColor := color.RGBA{uint8(r), uint8(g), uint8(b), 255}
+	fill := sd.currentFill(rgbaColor)
+
+	sd.writeMu.Lock()
+	unchanged := sd.isFilledWith(btnIndex, fill)
+	sd.writeMu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, sd.model.keySize, sd.model.keySize))
 	draw.Draw(img, img.Bounds(), image.NewUniform(rgbaColor), image.Point{0, 0}, draw.Src)
 
-	return sd.FillImage(btnIndex, img)
+	if err := sd.FillImage(btnIndex, img); err != nil {
+		return err
+	}
+	sd.setKeySource(btnIndex, LayoutKey{Color: hexColor(r, g, b)})
+
+	sd.writeMu.Lock()
+	if sd.cache[btnIndex].buf != nil {
+		sd.cache[btnIndex].fill = &fill
+	}
+	sd.writeMu.Unlock()
+	return nil
 }
 
 // FillImage fills the given key with an image. For best performance, provide
-// the image in the size of 72x72 pixels. Otherwise it will be automatically
-// resized.
+// the image in the key size of the model (72x72 pixels on the original
+// Stream Deck, see Capabilities). Otherwise it will be automatically
+// resized according to the ScaleMode. Transparent areas of the image are
+// composited over the background color.
 func (sd *StreamDeck) FillImage(btnIndex int, img image.Image) error {
-	if err := checkValidKeyIndex(btnIndex); err != nil {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
 		return err
 	}
 
+	sd.Lock()
+	scaleMode := sd.scaleMode
+	sd.Unlock()
+
 	// if necessary, rescale the picture
+	size := sd.model.keySize
 	rect := img.Bounds()
-	if rect.Dx() != ButtonSize {
-		img = resize(img, ButtonSize, ButtonSize)
-	}
-
-	imgBuf := make([]byte, 0, ButtonSize*ButtonSize*3)
-
-	for row := 0; row < ButtonSize; row++ {
-		for line := ButtonSize - 1; line >= 0; line-- {
-			r, g, b, _ := img.At(line, row).RGBA()
-			imgBuf = append(imgBuf, byte(r), byte(b), byte(g))
-		}
+	if rect.Dx() != size || rect.Dy() != size {
+		img = sd.scale(img, size, size, scaleMode)
 	}
 
-	page1 := imgBuf[0 : numFirstMsgPixels*3]
-	page2 := imgBuf[numFirstMsgPixels*3:]
-
-	sd.Lock()
-	defer sd.Unlock()
-	err := sd.writeMsg1(btnIndex, page1)
-	if err != nil {
-		return err
-	}
-	err = sd.writeMsg2(btnIndex, page2)
-	if err != nil {
-		return err
-	}
-	return nil
+	return sd.render(sd.model.btnRect(btnIndex), img)
 }
 
 // FillImageFromFile fills the given key with an image from a file.
@@ -333,50 +1222,70 @@ func (sd *StreamDeck) FillImageFromFile(keyIndex int, path string) error {
 		return err
 	}
 
-	return sd.FillImage(keyIndex, img)
+	if err := sd.FillImage(keyIndex, img); err != nil {
+		return err
+	}
+	sd.setKeySource(keyIndex, LayoutKey{Image: path})
+	return nil
 }
 
 // FillPanel fills the whole panel witn an image. The image is scaled to fit
-// and then center-cropped (if necessary). The native picture size is 360px x 216px.
+// and then center-cropped (if necessary). The native picture size depends on
+// the PanelGapMode and the model; with PanelGapIncluded it is the PanelWidth
+// x PanelHeight of the Capabilities (436px x 254px on the original Stream
+// Deck), with PanelGapInserted the keys placed next to each other (360px x
+// 216px).
 func (sd *StreamDeck) FillPanel(img image.Image) error {
 
+	sd.Lock()
+	gapMode := sd.panelGapMode
+	sd.Unlock()
+
+	width, height := sd.panelSize(gapMode)
+
 	// resize if the picture width is larger or smaller than panel
 	rect := img.Bounds()
-	if rect.Dx() != PanelWidth {
-		newWidthRatio := float32(rect.Dx()) / float32((PanelWidth))
-		img = resize(img, PanelWidth, int(float32(rect.Dy())/newWidthRatio))
+	if rect.Dx() != width {
+		newWidthRatio := float32(rect.Dx()) / float32((width))
+		img = resize(img, width, int(float32(rect.Dy())/newWidthRatio))
 	}
 
-	// if the Canvas is larger than PanelWidth x PanelHeight then we crop
-	// the Center match PanelWidth x PanelHeight
+	// if the Canvas is larger than the panel then we crop
+	// the Center match the panel size
 	rect = img.Bounds()
-	if rect.Dx() > PanelWidth || rect.Dy() > PanelHeight {
-		img = cropCenter(img, PanelWidth, PanelHeight)
-	}
-
-	counter := 0
-
-	for row := 0; row < NumButtonRows; row++ {
-		for col := 0; col < NumButtonColumns; col++ {
-			rect := image.Rectangle{
-				Min: image.Point{
-					X: PanelWidth - ButtonSize - col*ButtonSize - col*Spacer,
-					Y: row*ButtonSize + row*Spacer,
-				},
-				Max: image.Point{
-					X: PanelWidth - 1 - col*ButtonSize - col*Spacer,
-					Y: ButtonSize - 1 + row*ButtonSize + row*Spacer,
-				},
-			}
-			err := sd.FillImage(counter, img.(*image.RGBA).SubImage(rect))
-			if err != nil {
-				return err
-			}
-			counter++
-		}
+	if rect.Dx() > width || rect.Dy() > height {
+		img = cropCenter(img, width, height)
 	}
 
-	return nil
+	if gapMode == PanelGapInserted {
+		img = sd.insertGaps(img)
+	}
+
+	return sd.render(sd.model.panelRect(), img)
+}
+
+// panelSize returns the size of an image covering the whole panel with the
+// given PanelGapMode.
+func (sd *StreamDeck) panelSize(gapMode PanelGapMode) (width, height int) {
+	if gapMode == PanelGapInserted {
+		return sd.model.columns * sd.model.keySize, sd.model.rows * sd.model.keySize
+	}
+	rect := sd.model.panelRect()
+	return rect.Dx(), rect.Dy()
+}
+
+// FillPanelOver fills the whole panel with an image like FillPanel, but
+// composites the image over the color bg before it is scaled and sliced
+// into the buttons. Use it for panel art with transparency, which would
+// otherwise be scaled with its transparent fringes and composited per
+// button. If bg is nil, the background color (see SetBackground) is used.
+func (sd *StreamDeck) FillPanelOver(img image.Image, bg color.Color) error {
+	if bg == nil {
+		sd.Lock()
+		bg = sd.background
+		sd.Unlock()
+	}
+	return sd.FillPanel(composite(img, bg))
 }
 
 // FillPanelFromFile fills the entire panel with an image from a file.
@@ -401,35 +1310,262 @@ func (sd *StreamDeck) FillPanelFromFile(path string) error {
 }
 
 // WriteText can write several lines of Text to a button. It is up to the
-// user to ensure that the lines fit properly on the button.
+// user to ensure that the lines fit properly on the button. The text is
+// rendered with the supersampling factor set by SetSupersampling. Lines
+// which can not be rendered are handled according to the TextErrorMode.
 func (sd *StreamDeck) WriteText(btnIndex int, textBtn TextButton) error {
 
-	if err := checkValidKeyIndex(btnIndex); err != nil {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
 		return err
 	}
 
-	img := image.NewRGBA(image.Rect(0, 0, ButtonSize, ButtonSize))
+	factor := sd.supersamplingFactor()
+
+	sd.Lock()
+	errorMode := sd.textErrorMode
+	sd.Unlock()
+
+	img := image.NewRGBA(image.Rect(0, 0, sd.model.keySize*factor, sd.model.keySize*factor))
 	bg := image.NewUniform(textBtn.BgColor)
 	// fill button with Background color
 	draw.Draw(img, img.Bounds(), bg, image.Point{0, 0}, draw.Src)
 
-	for _, line := range textBtn.Lines {
-		fontColor := image.NewUniform(line.FontColor)
-		c := freetype.NewContext()
-		c.SetDPI(72)
-		c.SetFont(line.Font)
-		c.SetFontSize(line.FontSize)
-		c.SetClip(img.Bounds())
-		c.SetDst(img)
-		c.SetSrc(fontColor)
-		pt := freetype.Pt(line.PosX, line.PosY+int(c.PointToFixed(24)>>6))
-
-		if _, err := c.DrawString(line.Text, pt); err != nil {
+	var lineErrs []string
+	for i, line := range textBtn.Lines {
+		if err := drawTextLine(img, line, factor); err != nil {
+			if errorMode == TextStrict {
+				return err
+			}
+			lineErrs = append(lineErrs, fmt.Sprintf("line %d: %v", i, err))
+		}
+	}
+
+	if err := sd.FillImage(btnIndex, downscale(img, factor)); err != nil {
+		return err
+	}
+
+	if len(lineErrs) > 0 {
+		return fmt.Errorf("unable to render %d of %d lines: %s",
+			len(lineErrs), len(textBtn.Lines), strings.Join(lineErrs, "; "))
+	}
+	return nil
+}
+
+// drawTextLine draws a line of a TextButton onto dst, which has the size of
+// a button multiplied with the supersampling factor. Vertical lines are
+// drawn onto a transparent canvas, which is rotated onto dst.
+func drawTextLine(dst *image.RGBA, line TextLine, factor int) error {
+	canvas := dst
+	if line.Vertical {
+		canvas = image.NewRGBA(dst.Bounds())
+	}
+
+	c := freetype.NewContext()
+	// scaling the DPI scales the font size with the supersampling factor
+	c.SetDPI(float64(72 * factor))
+	c.SetFont(line.Font)
+	c.SetFontSize(line.FontSize)
+	c.SetHinting(line.Hinting)
+	c.SetClip(canvas.Bounds())
+	c.SetDst(canvas)
+	c.SetSrc(image.NewUniform(line.FontColor))
+	pt := freetype.Pt(line.PosX*factor, line.PosY*factor+int(c.PointToFixed(24)>>6))
+
+	if _, err := c.DrawString(line.Text, pt); err != nil {
+		return err
+	}
+
+	if line.Vertical {
+		// the text runs along the x axis of the canvas; rotating it
+		// counter-clockwise lets it read from bottom to top
+		rotate := gift.Rotate90()
+		if line.TopToBottom {
+			rotate = gift.Rotate270()
+		}
+		g := gift.New(rotate)
+		rotated := image.NewRGBA(g.Bounds(canvas.Bounds()))
+		g.Draw(rotated, canvas)
+		draw.Draw(dst, dst.Bounds(), rotated, rotated.Bounds().Min, draw.Over)
+	}
+	return nil
+}
+
+// Target is the destination of an image rendered with Render. It is either
+// a single button or the whole panel.
+type Target int
+
+// PanelTarget is the Target covering the whole panel.
+const PanelTarget Target = -1
+
+// BtnTarget returns the Target of a single button.
+func BtnTarget(btnIndex int) Target {
+	return Target(btnIndex)
+}
+
+// Render draws an image onto the given Target. Images for a button are
+// handled like in FillImage, images for the whole panel like in FillPanel.
+func (sd *StreamDeck) Render(target Target, img image.Image) error {
+	if target == PanelTarget {
+		return sd.FillPanel(img)
+	}
+	return sd.FillImage(int(target), img)
+}
+
+// render distributes an image across all buttons located within target.
+// target is expressed in panel coordinates and the image must already be
+// scaled to the size of target.
+func (sd *StreamDeck) render(target image.Rectangle, img image.Image) error {
+	offset := img.Bounds().Min.Sub(target.Min)
+
+	for btnIndex := 0; btnIndex < sd.model.NumButtons; btnIndex++ {
+		rect := sd.model.btnRect(btnIndex)
+		if !rect.In(target) {
+			continue
+		}
+		if err := sd.writeBtnImage(btnIndex, subImage(img, rect.Add(offset))); err != nil {
 			return err
 		}
 	}
 
-	return sd.FillImage(btnIndex, img)
+	return nil
+}
+
+// writeBtnImage encodes an image with the size of a button and sends it
+// to the Stream Deck.
+func (sd *StreamDeck) writeBtnImage(btnIndex int, img image.Image) error {
+	rgba, imgBuf := sd.encodeBtnImage(btnIndex, img)
+
+	sd.writeMu.Lock()
+	defer sd.writeMu.Unlock()
+	sd.stopAnimation(btnIndex)
+	return sd.writeBtnBuf(btnIndex, rgba, imgBuf)
+}
+
+// encodeBtnImage composites an image with the key size of the model over
+// the background, applies the KeyMask and converts it into the image format
+// of the model, including the rotation (see SetKeyRotation) and the display
+// gamma correction. The composited, unrotated image is returned together
+// with the encoded image.
+func (sd *StreamDeck) encodeBtnImage(btnIndex int, img image.Image) (*image.RGBA, []byte) {
+	sd.Lock()
+	bg := sd.background
+	mask := sd.keyMask
+	gamma := sd.gamma
+	rotation := sd.rotation
+	if sd.rotationExempt[btnIndex] {
+		rotation = Rotate0
+	}
+	sd.Unlock()
+
+	rgba := copyRGBA(composite(img, bg))
+	mask.apply(rgba)
+	pixels := rotation.add(sd.model.imageRotation).apply(rgba)
+
+	if sd.model.imageFormat == ImageFormatJPEG {
+		if gamma != nil {
+			// the cached image must not be modified
+			if pixels == rgba {
+				pixels = copyRGBA(rgba)
+			}
+			gamma.apply(pixels.Pix)
+		}
+		return rgba, encodeJPEG(pixels)
+	}
+
+	size := sd.model.keySize
+	imgBuf := make([]byte, 0, size*size*3)
+
+	for row := 0; row < size; row++ {
+		for line := size - 1; line >= 0; line-- {
+			// the image is opaque after compositing, so the premultiplied
+			// values equal the color values.
+			imgBuf = sd.model.channelOrder.appendPixel(imgBuf, pixels.RGBAAt(line, row))
+		}
+	}
+	gamma.apply(imgBuf)
+
+	return rgba, imgBuf
+}
+
+// writeBtnBuf sends the encoded pixels of a button to the Stream Deck and
+// updates the button cache. A badge shown on the button is discarded. If
+// the write fails, the error image is shown (see SetErrorImage). The write
+// lock must be held by the caller.
+func (sd *StreamDeck) writeBtnBuf(btnIndex int, img *image.RGBA, imgBuf []byte) error {
+	if sd.model.imageFormat == ImageFormatBMP {
+		size := sd.model.keySize
+		if len(imgBuf) != size*size*3 {
+			return fmt.Errorf("invalid image payload of %d bytes, expected %d bytes",
+				len(imgBuf), size*size*3)
+		}
+	} else if len(imgBuf) == 0 {
+		return fmt.Errorf("empty image payload")
+	}
+
+	if err := sd.sendBtnBuf(btnIndex, imgBuf); err != nil {
+		sd.showErrorImage(btnIndex)
+		return err
+	}
+	sd.cache[btnIndex] = btnCache{img: img, buf: imgBuf}
+	delete(sd.badgeBase, btnIndex)
+	return nil
+}
+
+// sendBtnBuf sends the encoded image of a button to the Stream Deck
+// without updating the button cache. The write lock must be held by the
+// caller.
+func (sd *StreamDeck) sendBtnBuf(btnIndex int, imgBuf []byte) error {
+	if sd.model.protocolVersion == 2 {
+		return sd.writeImagePages(btnIndex, imgBuf)
+	}
+	if sd.model.pagedBMP {
+		return sd.writeBMPPages(btnIndex, imgBuf)
+	}
+	if err := sd.writeMsg1(btnIndex, imgBuf[:numFirstMsgPixels*3]); err != nil {
+		return err
+	}
+	return sd.writeMsg2(btnIndex, imgBuf[numFirstMsgPixels*3:])
+}
+
+// blankBtnBuf returns the encoded image of a black button.
+func (sd *StreamDeck) blankBtnBuf() []byte {
+	size := sd.model.keySize
+	if sd.model.imageFormat == ImageFormatJPEG {
+		img := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(img, img.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+		return encodeJPEG(img)
+	}
+	return make([]byte, size*size*3)
+}
+
+// insertGaps returns a copy of an image without spacers, in which the
+// spacers between the buttons have been inserted. The result has the size
+// of the panel of the model.
+func (sd *StreamDeck) insertGaps(img image.Image) image.Image {
+	m := sd.model
+	res := image.NewRGBA(m.panelRect())
+	min := img.Bounds().Min
+	for row := 0; row < m.rows; row++ {
+		for col := 0; col < m.columns; col++ {
+			src := image.Pt(min.X+col*m.keySize, min.Y+row*m.keySize)
+			dst := image.Rect(col*(m.keySize+m.spacer), row*(m.keySize+m.spacer),
+				col*(m.keySize+m.spacer)+m.keySize, row*(m.keySize+m.spacer)+m.keySize)
+			draw.Draw(res, dst, img, src, draw.Src)
+		}
+	}
+	return res
+}
+
+// subImage returns the part of img located within rect.
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(rect)
+	}
+	res := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(res, res.Bounds(), img, rect.Min, draw.Src)
+	return res
 }
 
 // writeMsg1 writes the first part of a button's content to the stream deck.
@@ -441,8 +1577,7 @@ func (sd *StreamDeck) writeMsg1(btnIndex int, c []byte) error {
 		'\x00', '\xC0', '\x3C', '\x00', '\x00', '\xC4', '\x0E', '\x00', '\x00', '\xC4', '\x0E', '\x00', '\x00',
 		'\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00'}
 	merged := append(prefix, c...)
-	_, err := sd.device.write(merged)
-	return err
+	return sd.writeReport(merged)
 }
 
 // writeMsg2 writes the second part of a button's content to the stream deck.
@@ -450,8 +1585,111 @@ func (sd *StreamDeck) writeMsg2(btnIndex int, c []byte) error {
 	prefix := []byte{'\x02', '\x01', '\x02', '\x00', '\x01', byte(btnIndex + 1), '\x00', '\x00', '\x00', '\x00',
 		'\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00'}
 	merged := append(prefix, c...)
-	_, err := sd.device.write(merged)
-	return err
+	return sd.writeReport(merged)
+}
+
+// bmpPageHeaderSize is the size of the header of a report written by
+// writeBMPPages.
+const bmpPageHeaderSize = 16
+
+// bmpHeaderSize is the size of the header of a BMP file.
+const bmpHeaderSize = 54
+
+// writeBMPPages writes the content of a button together with the BMP
+// header in pages of the image report size of the model. The header of
+// each page contains the page number (counted from 0), whether it is the
+// last page and the key.
+func (sd *StreamDeck) writeBMPPages(btnIndex int, c []byte) error {
+	bmp := append(bmpHeader(sd.model.keySize), c...)
+	pageSize := sd.model.imageReportSize - bmpPageHeaderSize
+	for page := 0; page*pageSize < len(bmp); page++ {
+		payload := bmp[page*pageSize:]
+		last := byte(1)
+		if len(payload) > pageSize {
+			payload = payload[:pageSize]
+			last = 0
+		}
+
+		report := make([]byte, sd.model.imageReportSize)
+		copy(report, []byte{'\x02', '\x01', byte(page), '\x00', last, byte(btnIndex + 1)})
+		copy(report[bmpPageHeaderSize:], payload)
+		if err := sd.writeReport(report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bmpHeader returns the header of a 24 bit BMP file with size x size
+// pixels.
+func bmpHeader(size int) []byte {
+	imgSize := uint32(size * size * 3)
+	h := make([]byte, bmpHeaderSize)
+	h[0], h[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(h[2:], bmpHeaderSize+imgSize)
+	binary.LittleEndian.PutUint32(h[10:], bmpHeaderSize)
+	binary.LittleEndian.PutUint32(h[14:], 40)
+	binary.LittleEndian.PutUint32(h[18:], uint32(size))
+	binary.LittleEndian.PutUint32(h[22:], uint32(size))
+	binary.LittleEndian.PutUint16(h[26:], 1)
+	binary.LittleEndian.PutUint16(h[28:], 24)
+	binary.LittleEndian.PutUint32(h[34:], imgSize)
+	// 3780 pixel per meter (96 dpi)
+	binary.LittleEndian.PutUint32(h[38:], 3780)
+	binary.LittleEndian.PutUint32(h[42:], 3780)
+	return h
+}
+
+// imageHeaderSize is the size of the header of an image report of protocol
+// version 2.
+const imageHeaderSize = 8
+
+// writeImagePages writes an encoded image in pages of the image report size
+// of the model (protocol version 2). The header of each page contains the
+// key, whether it is the last page, the length of the payload and the page
+// number.
+func (sd *StreamDeck) writeImagePages(btnIndex int, imgBuf []byte) error {
+	pageSize := sd.model.imageReportSize - imageHeaderSize
+	for page := 0; page*pageSize < len(imgBuf) || page == 0; page++ {
+		payload := imgBuf[page*pageSize:]
+		last := byte(1)
+		if len(payload) > pageSize {
+			payload = payload[:pageSize]
+			last = 0
+		}
+
+		report := make([]byte, sd.model.imageReportSize)
+		copy(report, []byte{'\x02', '\x07', byte(btnIndex), last,
+			byte(len(payload)), byte(len(payload) >> 8), byte(page), byte(page >> 8)})
+		copy(report[imageHeaderSize:], payload)
+		if err := sd.writeReport(report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeReport writes an output report, starting with the report ID, to the
+// Stream Deck and ensures that it has been transmitted completely. The
+// report ID is left out if the backend adds it itself (see
+// ReportIDPrefixer). Reports exceeding the output report size of the model
+// are rejected, as the firmware would misinterpret them.
+func (sd *StreamDeck) writeReport(report []byte) error {
+	if len(report) > sd.model.OutputReportSize {
+		return fmt.Errorf("output report of %d bytes exceeds the maximum size of %d bytes",
+			len(report), sd.model.OutputReportSize)
+	}
+	if p, ok := sd.device.(ReportIDPrefixer); ok && p.PrefixesReportID() {
+		report = report[1:]
+	}
+	n, err := sd.device.Write(report)
+	if err != nil {
+		return err
+	}
+	if n != len(report) {
+		return fmt.Errorf("short write to stream deck: %d of %d bytes", n, len(report))
+	}
+	return nil
 }
 
 // resize returns a resized copy of the supplied image with the given width and height.
@@ -465,6 +1703,49 @@ func resize(img image.Image, width, height int) image.Image {
 	return res
 }
 
+// scale returns a copy of the supplied image scaled to the given width and
+// height according to the ScaleMode.
+func scale(img image.Image, width, height int, mode ScaleMode) image.Image {
+	rect := img.Bounds()
+
+	switch mode {
+	case ScaleFit, ScaleFill:
+		ratioX := float64(width) / float64(rect.Dx())
+		ratioY := float64(height) / float64(rect.Dy())
+		ratio := ratioX
+		if (mode == ScaleFit && ratioY < ratioX) || (mode == ScaleFill && ratioY > ratioX) {
+			ratio = ratioY
+		}
+		w := int(float64(rect.Dx())*ratio + 0.5)
+		h := int(float64(rect.Dy())*ratio + 0.5)
+		scaled := resize(img, w, h)
+		if mode == ScaleFill {
+			return cropCenter(scaled, width, height)
+		}
+		// center the scaled image on a transparent canvas. The padding
+		// will be filled with the background color during compositing.
+		res := image.NewRGBA(image.Rect(0, 0, width, height))
+		pos := image.Pt((width-w)/2, (height-h)/2)
+		draw.Draw(res, scaled.Bounds().Add(pos), scaled, image.Point{0, 0}, draw.Src)
+		return res
+	default:
+		return resize(img, width, height)
+	}
+}
+
+// composite returns the supplied image composited over the background color.
+// Opaque images are returned unchanged.
+func composite(img image.Image, bg color.Color) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+	rect := img.Bounds()
+	res := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(res, res.Bounds(), image.NewUniform(bg), image.Point{0, 0}, draw.Src)
+	draw.Draw(res, res.Bounds(), img, rect.Min, draw.Over)
+	return res
+}
+
 // crop center will extract a sub image with the given width and height
 // from the center of the supplied picture.
 func cropCenter(img image.Image, width, height int) image.Image {
@@ -476,12 +1757,9 @@ func cropCenter(img image.Image, width, height int) image.Image {
 	return res
 }
 
-// checkValidKeyIndex checks that the keyIndex is valid
-func checkValidKeyIndex(keyIndex int) error {
-	if keyIndex < 0 || keyIndex > 15 {
-		return fmt.Errorf("invalid key index")
-	}
-	return nil
+// checkValidKeyIndex checks that the keyIndex is valid for the model
+func (sd *StreamDeck) checkValidKeyIndex(keyIndex int) error {
+	return sd.model.validKeyIndex(keyIndex)
 }
 
 // checkRGB returns an error in case of an invalid color (8 bit)
@@ -499,3 +1777,11 @@ func intToButtonState(i int) BtnState {
 	}
 	return BtnPressed
 }
+
+// invertButtonState returns the opposite ButtonState
+func invertButtonState(state BtnState) BtnState {
+	if state == BtnPressed {
+		return BtnReleased
+	}
+	return BtnPressed
+}
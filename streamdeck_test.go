@@ -0,0 +1,256 @@
+package StreamDeck
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestDeck returns a StreamDeck writing to a VirtualDevice of the model
+// with the given product ID.
+func newTestDeck(t *testing.T, productID uint16) (*StreamDeck, *VirtualDevice) {
+	t.Helper()
+	vd, err := NewVirtualDeviceWithModel(productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, err := NewStreamDeckWithDevice(nil, vd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sd, vd
+}
+
+// keyCenter returns the color in the center of a key as shown by the
+// virtual device.
+func keyCenter(sd *StreamDeck, vd *VirtualDevice, btnIndex int) color.RGBA {
+	rect := sd.model.btnRect(btnIndex)
+	center := rect.Min.Add(rect.Size().Div(2))
+	return vd.Image().RGBAAt(center.X, center.Y)
+}
+
+// colorNear reports whether the channels of a and b differ by at most 2,
+// allowing for rounding in the compositing.
+func colorNear(a, b color.RGBA) bool {
+	near := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return d >= -2 && d <= 2
+	}
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && near(a.A, b.A)
+}
+
+func TestFillImageTransparency(t *testing.T) {
+	sd, vd := newTestDeck(t, ProductID)
+	sd.SetBackground(color.White)
+
+	img := image.NewNRGBA(image.Rect(0, 0, ButtonSize, ButtonSize))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.NRGBA{255, 0, 0, 128}), image.Point{0, 0}, draw.Src)
+	if err := sd.FillImage(0, img); err != nil {
+		t.Fatal(err)
+	}
+
+	want := color.RGBA{255, 127, 127, 255}
+	if got := keyCenter(sd, vd, 0); !colorNear(got, want) {
+		t.Errorf("50%% red over white = %v, want %v", got, want)
+	}
+}
+
+func TestFillColorOpaque(t *testing.T) {
+	sd, vd := newTestDeck(t, ProductID)
+	sd.SetBackground(color.White)
+
+	if err := sd.FillColor(0, 255, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := color.RGBA{255, 0, 0, 255}
+	if got := keyCenter(sd, vd, 0); got != want {
+		t.Errorf("FillColor(255, 0, 0) = %v, want %v", got, want)
+	}
+}
+
+// serve runs ServeContext in a goroutine and returns a function which stops
+// it and returns its error.
+func serve(t *testing.T, sd *StreamDeck) (stop func() error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- sd.ServeContext(ctx)
+	}()
+	return func() error {
+		cancel()
+		select {
+		case err := <-result:
+			return err
+		case <-time.After(2 * time.Second):
+			t.Fatal("ServeContext did not return")
+			return nil
+		}
+	}
+}
+
+func TestSyncDispatchDrawing(t *testing.T) {
+	sd, vd := newTestDeck(t, ProductID)
+	sd.SetSyncDispatch(true)
+
+	drawn := make(chan error, 1)
+	sd.SetBtnEventCb(func(btnIndex int, state BtnState) {
+		if state != BtnPressed {
+			return
+		}
+		img := image.NewRGBA(image.Rect(0, 0, ButtonSize, ButtonSize))
+		drawn <- sd.FillImage(btnIndex, img)
+	})
+	stop := serve(t, sd)
+	defer stop()
+
+	if err := vd.Press(3); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-drawn:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("FillImage within a synchronous handler deadlocked")
+	}
+}
+
+func TestServeConcurrently(t *testing.T) {
+	sd, _ := newTestDeck(t, ProductID)
+	stop := serve(t, sd)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&sd.serving) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("ServeContext did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := sd.Serve(make(chan bool)); err != ErrAlreadyServing {
+		t.Errorf("second Serve returned %v, want ErrAlreadyServing", err)
+	}
+	if err := sd.ServeContext(context.Background()); err != ErrAlreadyServing {
+		t.Errorf("second ServeContext returned %v, want ErrAlreadyServing", err)
+	}
+
+	if err := stop(); err != context.Canceled {
+		t.Errorf("ServeContext returned %v, want context.Canceled", err)
+	}
+
+	// serving again is possible once the first call has returned
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sd.ServeContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("ServeContext after stop returned %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestKeyNumbering(t *testing.T) {
+	for _, m := range models {
+		t.Run(m.Name, func(t *testing.T) {
+			sd, vd := newTestDeck(t, m.ProductID)
+			sd.SetSyncDispatch(true)
+
+			events := make(chan Event, 1)
+			sd.SetBtnEventCb(func(btnIndex int, state BtnState) {
+				if state == BtnPressed {
+					events <- Event{BtnIndex: btnIndex, State: state}
+				}
+			})
+			stop := serve(t, sd)
+			defer stop()
+
+			white := color.RGBA{255, 255, 255, 255}
+			for i := 0; i < m.NumButtons; i++ {
+				sd.ClearAllBtns()
+				if err := sd.FillColor(i, 255, 255, 255); err != nil {
+					t.Fatal(err)
+				}
+				for j := 0; j < m.NumButtons; j++ {
+					lit := colorNear(keyCenter(sd, vd, j), white)
+					if lit != (i == j) {
+						t.Fatalf("after filling key %d, key %d lit = %v", i, j, lit)
+					}
+				}
+
+				if err := vd.Press(i); err != nil {
+					t.Fatal(err)
+				}
+				select {
+				case ev := <-events:
+					if ev.BtnIndex != i {
+						t.Errorf("pressing key %d reported key %d", i, ev.BtnIndex)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatalf("no event for key %d", i)
+				}
+				if err := vd.Release(i); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// positionImage returns an image whose pixels encode their own coordinates.
+func positionImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, positionColor(x, y))
+		}
+	}
+	return img
+}
+
+func positionColor(x, y int) color.RGBA {
+	return color.RGBA{uint8(x), uint8(y), uint8(x>>8 | (y>>8)<<4), 255}
+}
+
+func TestFillPanelGapModes(t *testing.T) {
+	// the models transmitting lossless BMP images
+	for _, m := range []Model{modelOriginal, modelMini} {
+		for name, mode := range map[string]PanelGapMode{
+			"PanelGapIncluded": PanelGapIncluded,
+			"PanelGapInserted": PanelGapInserted,
+		} {
+			t.Run(m.Name+"/"+name, func(t *testing.T) {
+				sd, vd := newTestDeck(t, m.ProductID)
+				sd.SetPanelGapMode(mode)
+
+				width, height := sd.panelSize(mode)
+				if err := sd.FillPanel(positionImage(width, height)); err != nil {
+					t.Fatal(err)
+				}
+
+				panel := vd.Image()
+				size := m.keySize
+				for i := 0; i < m.NumButtons; i++ {
+					rect := m.btnRect(i)
+					// the origin of the key within the source image
+					src := rect.Min
+					if mode == PanelGapInserted {
+						col := rect.Min.X / (size + m.spacer)
+						row := rect.Min.Y / (size + m.spacer)
+						src = image.Pt(col*size, row*size)
+					}
+					for _, d := range []image.Point{{0, 0}, {size - 1, 0}, {0, size - 1}, {size - 1, size - 1}} {
+						got := panel.RGBAAt(rect.Min.X+d.X, rect.Min.Y+d.Y)
+						want := positionColor(src.X+d.X, src.Y+d.Y)
+						if got != want {
+							t.Errorf("key %d at %v = %v, want %v", i, d, got, want)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"text/template"
+)
+
+// templateErrorColor is the background of the indicator drawn by
+// WriteTemplate when a template can not be rendered.
+var templateErrorColor = color.RGBA{200, 0, 0, 255}
+
+// keyTemplate is a parsed template of WriteTemplate together with its
+// source.
+type keyTemplate struct {
+	src  string
+	tmpl *template.Template
+}
+
+// WriteTemplate renders a text/template with data and writes the result
+// onto a button like FillText, e.g. "{{.CPU}}%". The parsed template is
+// cached per button, so repeatedly writing the same template with new data
+// doesn't parse it again. If the template can not be parsed or executed, a
+// red key with an exclamation mark is drawn as indicator and the error is
+// returned.
+func (sd *StreamDeck) WriteTemplate(btnIndex int, tmpl string, data interface{}) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+
+	t, err := sd.keyTemplate(btnIndex, tmpl)
+	if err != nil {
+		sd.drawTemplateError(btnIndex)
+		return fmt.Errorf("unable to parse template of button %d: %v", btnIndex, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		sd.drawTemplateError(btnIndex)
+		return fmt.Errorf("unable to execute template of button %d: %v", btnIndex, err)
+	}
+
+	return sd.FillText(btnIndex, buf.String())
+}
+
+// keyTemplate returns the parsed template of a button, parsing it if the
+// button doesn't have a cached template with the same source.
+func (sd *StreamDeck) keyTemplate(btnIndex int, src string) (*template.Template, error) {
+	sd.Lock()
+	cached, ok := sd.templates[btnIndex]
+	sd.Unlock()
+	if ok && cached.src == src {
+		return cached.tmpl, nil
+	}
+
+	t, err := template.New(fmt.Sprintf("button%d", btnIndex)).Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	sd.Lock()
+	defer sd.Unlock()
+	if sd.templates == nil {
+		sd.templates = make(map[int]keyTemplate)
+	}
+	sd.templates[btnIndex] = keyTemplate{src: src, tmpl: t}
+	return t, nil
+}
+
+// drawTemplateError draws the error indicator of WriteTemplate. Errors are
+// only logged, since the error of the template is more relevant.
+func (sd *StreamDeck) drawTemplateError(btnIndex int) {
+	err := sd.DrawKey(btnIndex, func(dst *image.RGBA) {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(templateErrorColor), image.Point{0, 0}, draw.Src)
+		factor := dst.Bounds().Dx() / sd.model.keySize
+		drawCenteredText(dst, "!", image.White, factor)
+	})
+	if err != nil {
+		sd.log.Warn(err.Error())
+	}
+}
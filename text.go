@@ -0,0 +1,181 @@
+package StreamDeck
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/math/fixed"
+)
+
+// limits of the font size (in points) chosen by FillText
+const (
+	fillTextMaxSize = 32
+	fillTextMinSize = 8
+)
+
+// fillTextMargin is the distance (in pixel) between the text written by
+// FillText and the border of the button.
+const fillTextMargin = 4
+
+var (
+	defaultFont     *truetype.Font
+	defaultFontErr  error
+	defaultFontOnce sync.Once
+)
+
+// loadDefaultFont parses the embedded default font once.
+func loadDefaultFont() (*truetype.Font, error) {
+	defaultFontOnce.Do(func() {
+		defaultFont, defaultFontErr = truetype.Parse(gomedium.TTF)
+	})
+	return defaultFont, defaultFontErr
+}
+
+// FillText writes white text on black background centered onto a button.
+// It uses an embedded default font, so no font has to be loaded. Lines are
+// separated by "\n". The largest font size with which all lines fit onto the
+// button is chosen automatically; text which doesn't fit even with the
+// smallest size is clipped. Use WriteText for full control over the layout.
+func (sd *StreamDeck) FillText(btnIndex int, text string) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+
+	factor := sd.supersamplingFactor()
+	size := sd.model.keySize * factor
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+
+	if err := drawCenteredText(img, text, image.White, factor); err != nil {
+		return err
+	}
+
+	if err := sd.FillImage(btnIndex, downscale(img, factor)); err != nil {
+		return err
+	}
+	sd.setKeySource(btnIndex, LayoutKey{Text: text})
+	return nil
+}
+
+// drawCenteredText draws text centered onto dst with the embedded default
+// font, choosing the font size like FillText. dst must have the size of a
+// button multiplied with the supersampling factor.
+func drawCenteredText(dst *image.RGBA, text string, src image.Image, factor int) error {
+	f, err := loadDefaultFont()
+	if err != nil {
+		return err
+	}
+
+	rect := dst.Bounds()
+	avail := rect.Dx() - 2*fillTextMargin*factor
+	lines := strings.Split(text, "\n")
+
+	var face font.Face
+	for pt := fillTextMaxSize; pt >= fillTextMinSize; pt-- {
+		face = truetype.NewFace(f, &truetype.Options{
+			Size:    float64(pt),
+			DPI:     float64(72 * factor),
+			Hinting: font.HintingFull,
+		})
+		if fitsText(face, lines, avail) {
+			break
+		}
+	}
+
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  src,
+		Face: face,
+	}
+
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	top := rect.Min.Y + (rect.Dy()-lineHeight*len(lines))/2
+	for i, line := range lines {
+		width := d.MeasureString(line).Ceil()
+		d.Dot = fixed.P(rect.Min.X+(rect.Dx()-width)/2, top+i*lineHeight+metrics.Ascent.Ceil())
+		d.DrawString(line)
+	}
+
+	return nil
+}
+
+// fitsText returns true if all lines rendered with face fit into a square
+// with the edge length avail.
+func fitsText(face font.Face, lines []string, avail int) bool {
+	if face.Metrics().Height.Ceil()*len(lines) > avail {
+		return false
+	}
+	for _, line := range lines {
+		if font.MeasureString(face, line).Ceil() > avail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteGlyph draws a single glyph of a font centered onto a button, which
+// allows to use icon fonts (like Font Awesome) instead of image files. The
+// glyph is selected by its codepoint and drawn with size points in the
+// color fg on the background bg. The glyph is centered by its visible
+// bounds, not by its advance. If bg is nil, the background is black; if fg
+// is nil, black or white is chosen, whichever contrasts best with bg. An
+// error is returned if the font doesn't contain the codepoint.
+func (sd *StreamDeck) WriteGlyph(btnIndex int, f *truetype.Font, codepoint rune, size float64, fg, bg color.Color) error {
+	if err := sd.checkValidKeyIndex(btnIndex); err != nil {
+		return err
+	}
+	if f == nil {
+		return fmt.Errorf("font must not be nil")
+	}
+	if size <= 0 {
+		return fmt.Errorf("glyph size must be positive")
+	}
+	if f.Index(codepoint) == 0 {
+		return fmt.Errorf("font has no glyph for codepoint %U", codepoint)
+	}
+	if bg == nil {
+		bg = color.Black
+	}
+	if fg == nil {
+		fg = ContrastColor(bg)
+	}
+
+	return sd.DrawKey(btnIndex, func(dst *image.RGBA) {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{0, 0}, draw.Src)
+
+		factor := dst.Bounds().Dx() / sd.model.keySize
+		face := truetype.NewFace(f, &truetype.Options{
+			Size: size,
+			DPI:  float64(72 * factor),
+		})
+		defer face.Close()
+
+		bounds, _, ok := face.GlyphBounds(codepoint)
+		if !ok {
+			return
+		}
+
+		// move the center of the glyph bounds onto the center of the button
+		rect := dst.Bounds()
+		center := fixed.P(rect.Min.X+rect.Dx()/2, rect.Min.Y+rect.Dy()/2)
+		d := &font.Drawer{
+			Dst:  dst,
+			Src:  image.NewUniform(fg),
+			Face: face,
+			Dot: fixed.Point26_6{
+				X: center.X - (bounds.Min.X+bounds.Max.X)/2,
+				Y: center.Y - (bounds.Min.Y+bounds.Max.Y)/2,
+			},
+		}
+		d.DrawString(string(codepoint))
+	})
+}
@@ -0,0 +1,83 @@
+package StreamDeck
+
+import (
+	"time"
+)
+
+// DefaultReconnectLogInterval is the default interval in which identical
+// reconnect failures are logged at most once.
+const DefaultReconnectLogInterval = time.Minute
+
+// logThrottle suppresses identical log messages within an interval. It
+// keeps count of the suppressed messages, so that they can be reported
+// with the next message logged.
+type logThrottle struct {
+	interval   time.Duration
+	last       string
+	lastTime   time.Time
+	suppressed int
+}
+
+// allow returns true if msg should be logged at the point in time now,
+// together with the amount of messages suppressed since the last logged
+// message.
+func (t *logThrottle) allow(msg string, now time.Time) (bool, int) {
+	if t.interval > 0 && msg == t.last && now.Sub(t.lastTime) < t.interval {
+		t.suppressed++
+		return false, 0
+	}
+	suppressed := t.suppressed
+	t.last = msg
+	t.lastTime = now
+	t.suppressed = 0
+	return true, suppressed
+}
+
+// reset forgets the last message and returns the amount of messages
+// suppressed since it has been logged.
+func (t *logThrottle) reset() int {
+	suppressed := t.suppressed
+	t.last = ""
+	t.suppressed = 0
+	return suppressed
+}
+
+// SetReconnectLogInterval sets the interval in which identical reconnect
+// failures are logged at most once. Suppressed failures are counted and
+// reported with the next message. During a long outage this prevents a
+// retry loop around Serve from flooding the log. An interval of zero logs
+// every failure. The default is DefaultReconnectLogInterval.
+func (sd *StreamDeck) SetReconnectLogInterval(interval time.Duration) {
+	sd.Lock()
+	defer sd.Unlock()
+	sd.reconnectLog.interval = interval
+}
+
+// logReconnectFailure logs a failed reconnect attempt, rate-limited by the
+// reconnect log interval.
+func (sd *StreamDeck) logReconnectFailure(err error) {
+	sd.Lock()
+	ok, suppressed := sd.reconnectLog.allow(err.Error(), time.Now())
+	sd.Unlock()
+
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		sd.log.Warnf("reconnect failed: %v (%d similar failures suppressed)", err, suppressed)
+		return
+	}
+	sd.log.Warnf("reconnect failed: %v", err)
+}
+
+// logReconnected logs a successful reconnect if previous failures have
+// been suppressed.
+func (sd *StreamDeck) logReconnected() {
+	sd.Lock()
+	suppressed := sd.reconnectLog.reset()
+	sd.Unlock()
+
+	if suppressed > 0 {
+		sd.log.Infof("reconnected after %d suppressed failures", suppressed)
+	}
+}
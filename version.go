@@ -0,0 +1,26 @@
+package StreamDeck
+
+// version is the version of the package. It can be set at build time with
+//
+//	go build -ldflags "-X github.com/AKovalevich/streamdeck.version=v1.2.3"
+var version = "v0.9.0-dev"
+
+// Version returns the version of the package. Tools communicating with
+// each other (e.g. through the web or rest package) can use it to check
+// their compatibility.
+func Version() string {
+	return version
+}
+
+// ProtocolVersion returns the generation of the image protocol used by the
+// model with the given name (see Capabilities). The first generation
+// transmits BMP images in two reports or in pages per key; later generations
+// differ in image format and report layout. 0 is returned for unknown models.
+func ProtocolVersion(model string) int {
+	for _, m := range models {
+		if m.Name == model {
+			return m.protocolVersion
+		}
+	}
+	return 0
+}
@@ -0,0 +1,362 @@
+package StreamDeck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sync"
+	"time"
+)
+
+// VirtualDevice is an in-memory Device which emulates a Stream Deck. Instead
+// of sending the button images via USB, they are rendered into an image of
+// the panel. Button presses can be simulated with Press and Release. The
+// VirtualDevice is intended for development and testing without hardware.
+type VirtualDevice struct {
+	sync.Mutex
+	model     Model
+	connected bool
+	panel     *image.RGBA
+	page1     map[int][]byte
+	btnState  []byte
+	reports   chan []byte
+	done      chan struct{}
+	timeout   time.Duration
+}
+
+// NewVirtualDevice is the constructor of a VirtualDevice emulating the
+// original 15 button Stream Deck.
+func NewVirtualDevice() *VirtualDevice {
+	return newVirtualDevice(modelOriginal)
+}
+
+// NewVirtualDeviceWithModel is the constructor of a VirtualDevice emulating
+// the model with the given ProductID, e.g. ProductIDXL.
+func NewVirtualDeviceWithModel(productID uint16) (*VirtualDevice, error) {
+	model, err := modelForProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if model.imageFormat == ImageFormatNone {
+		return nil, fmt.Errorf("emulation of the %s is not supported", model.Name)
+	}
+	return newVirtualDevice(model), nil
+}
+
+func newVirtualDevice(model Model) *VirtualDevice {
+	vd := &VirtualDevice{
+		model:    model,
+		panel:    image.NewRGBA(model.panelRect()),
+		page1:    make(map[int][]byte),
+		btnState: make([]byte, model.NumButtons),
+		reports:  make(chan []byte, 64),
+	}
+	draw.Draw(vd.panel, vd.panel.Bounds(), image.Black, image.Point{0, 0}, draw.Src)
+	return vd
+}
+
+// Connect connects the virtual device.
+func (vd *VirtualDevice) Connect() error {
+	vd.Lock()
+	defer vd.Unlock()
+	if !vd.connected {
+		vd.connected = true
+		vd.done = make(chan struct{})
+	}
+	return nil
+}
+
+// Close disconnects the virtual device. A pending Read returns with an error.
+func (vd *VirtualDevice) Close() error {
+	vd.Lock()
+	defer vd.Unlock()
+	if vd.connected {
+		vd.connected = false
+		close(vd.done)
+	}
+	return nil
+}
+
+// IsConnected returns true if the virtual device is connected.
+func (vd *VirtualDevice) IsConnected() bool {
+	vd.Lock()
+	defer vd.Unlock()
+	return vd.connected
+}
+
+// GetSerialNumber returns the (fixed) serial number of the virtual device.
+func (vd *VirtualDevice) GetSerialNumber() (string, error) {
+	return "VIRTUAL", nil
+}
+
+// GetProductID returns the ProductID of the emulated model.
+func (vd *VirtualDevice) GetProductID() uint16 {
+	return vd.model.ProductID
+}
+
+// GetVendorID returns the Elgato VendorID.
+func (vd *VirtualDevice) GetVendorID() uint16 {
+	return VendorID
+}
+
+// SetReadTimeout sets the maximum duration Read waits for a simulated input
+// report, like USBDevice.SetReadTimeout.
+func (vd *VirtualDevice) SetReadTimeout(timeout time.Duration) {
+	vd.Lock()
+	defer vd.Unlock()
+	vd.timeout = timeout
+}
+
+// Read blocks until a simulated input report is available.
+func (vd *VirtualDevice) Read(data []byte) (int, error) {
+	return vd.ReadContext(context.Background(), data)
+}
+
+// ReadContext blocks until a simulated input report is available or ctx is
+// done.
+func (vd *VirtualDevice) ReadContext(ctx context.Context, data []byte) (int, error) {
+	vd.Lock()
+	done := vd.done
+	timeout := vd.timeout
+	vd.Unlock()
+
+	if done == nil {
+		return 0, errors.New("device not connected")
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case report := <-vd.reports:
+		return copy(data, report), nil
+	case <-done:
+		return 0, errors.New("device closed")
+	case <-expired:
+		return 0, ErrReadTimeout
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// Write decodes an image output report and renders it into the panel image.
+func (vd *VirtualDevice) Write(data []byte) (int, error) {
+	if vd.model.protocolVersion == 2 {
+		return vd.writeImagePage(data)
+	}
+	if vd.model.pagedBMP {
+		return vd.writeBMPPage(data)
+	}
+	if len(data) < 6 || data[0] != 0x02 || data[1] != 0x01 {
+		return 0, fmt.Errorf("unknown output report")
+	}
+	btnIndex := int(data[5]) - 1
+	if btnIndex < 0 || btnIndex >= vd.model.NumButtons {
+		return 0, fmt.Errorf("invalid key index")
+	}
+
+	vd.Lock()
+	defer vd.Unlock()
+
+	switch data[2] {
+	case 0x01:
+		if len(data) < numFirstMsgPixels*3 {
+			return 0, fmt.Errorf("image report too short")
+		}
+		vd.page1[btnIndex] = append([]byte(nil), data[len(data)-numFirstMsgPixels*3:]...)
+	case 0x02:
+		page1, ok := vd.page1[btnIndex]
+		if !ok {
+			return 0, fmt.Errorf("second image report received before the first one")
+		}
+		if len(data) < numSecondMsgPixels*3 {
+			return 0, fmt.Errorf("image report too short")
+		}
+		delete(vd.page1, btnIndex)
+		vd.drawBtn(btnIndex, append(page1, data[len(data)-numSecondMsgPixels*3:]...))
+	default:
+		return 0, fmt.Errorf("unknown image report page %d", data[2])
+	}
+
+	return len(data), nil
+}
+
+// writeBMPPage decodes a page of a BMP image (see Model.pagedBMP). The
+// pages of an image are collected until the last one has been received.
+func (vd *VirtualDevice) writeBMPPage(data []byte) (int, error) {
+	if len(data) < bmpPageHeaderSize || data[0] != 0x02 || data[1] != 0x01 {
+		return 0, fmt.Errorf("unknown output report")
+	}
+	btnIndex := int(data[5]) - 1
+	if btnIndex < 0 || btnIndex >= vd.model.NumButtons {
+		return 0, fmt.Errorf("invalid key index")
+	}
+	page := int(data[2])
+	last := data[4] != 0
+
+	vd.Lock()
+	defer vd.Unlock()
+
+	if page == 0 {
+		vd.page1[btnIndex] = nil
+	} else if _, ok := vd.page1[btnIndex]; !ok {
+		return 0, fmt.Errorf("image report page %d received before the first one", page)
+	}
+	vd.page1[btnIndex] = append(vd.page1[btnIndex], data[bmpPageHeaderSize:]...)
+
+	if last {
+		bmp := vd.page1[btnIndex]
+		delete(vd.page1, btnIndex)
+		n := vd.model.keySize * vd.model.keySize * 3
+		if len(bmp) < bmpHeaderSize+n {
+			return 0, fmt.Errorf("image of key %d too short", btnIndex)
+		}
+		vd.drawBtn(btnIndex, bmp[bmpHeaderSize:bmpHeaderSize+n])
+	}
+
+	return len(data), nil
+}
+
+// writeImagePage decodes an image report of protocol version 2. The pages
+// of an image are collected until the last one has been received.
+func (vd *VirtualDevice) writeImagePage(data []byte) (int, error) {
+	if len(data) < imageHeaderSize || data[0] != 0x02 || data[1] != 0x07 {
+		return 0, fmt.Errorf("unknown output report")
+	}
+	btnIndex := int(data[2])
+	if btnIndex >= vd.model.NumButtons {
+		return 0, fmt.Errorf("invalid key index")
+	}
+	last := data[3] != 0
+	length := int(data[4]) | int(data[5])<<8
+	page := int(data[6]) | int(data[7])<<8
+	if imageHeaderSize+length > len(data) {
+		return 0, fmt.Errorf("image report too short")
+	}
+
+	vd.Lock()
+	defer vd.Unlock()
+
+	if page == 0 {
+		vd.page1[btnIndex] = nil
+	} else if _, ok := vd.page1[btnIndex]; !ok {
+		return 0, fmt.Errorf("image report page %d received before the first one", page)
+	}
+	vd.page1[btnIndex] = append(vd.page1[btnIndex], data[imageHeaderSize:imageHeaderSize+length]...)
+
+	if last {
+		buf := vd.page1[btnIndex]
+		delete(vd.page1, btnIndex)
+		img, err := jpeg.Decode(bytes.NewReader(buf))
+		if err != nil {
+			return 0, fmt.Errorf("unable to decode image of key %d: %v", btnIndex, err)
+		}
+		// undo the rotation expected by the firmware
+		inverse := (4 - vd.model.imageRotation) % 4
+		rgba := inverse.apply(copyRGBA(img))
+		draw.Draw(vd.panel, vd.model.btnRect(btnIndex), rgba, image.Point{0, 0}, draw.Src)
+	}
+
+	return len(data), nil
+}
+
+// SendFeatureReport accepts any feature report.
+func (vd *VirtualDevice) SendFeatureReport(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("feature report must contain at least the report ID")
+	}
+	return nil
+}
+
+// virtualFirmware is the firmware version reported by a VirtualDevice.
+const virtualFirmware = "virtual"
+
+// GetFeatureReport answers the firmware version request of the emulated
+// model with a fixed version. Other feature reports are answered with
+// zeros.
+func (vd *VirtualDevice) GetFeatureReport(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errors.New("feature report must contain at least the report ID")
+	}
+	for i := 1; i < len(data); i++ {
+		data[i] = 0
+	}
+	if data[0] == vd.model.firmwareReportID && vd.model.firmwareOffset < len(data) {
+		copy(data[vd.model.firmwareOffset:], virtualFirmware)
+	}
+	return len(data), nil
+}
+
+// drawBtn renders the raw pixels of a button into the panel image. It is
+// the inverse of the encoding in writeBtnImage.
+func (vd *VirtualDevice) drawBtn(btnIndex int, pixels []byte) {
+	size := vd.model.keySize
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	i := 0
+	for row := 0; row < size; row++ {
+		for line := size - 1; line >= 0; line-- {
+			img.SetRGBA(line, row, vd.model.channelOrder.pixel(pixels[i:i+3]))
+			i += 3
+		}
+	}
+	// undo the rotation expected by the firmware
+	inverse := (4 - vd.model.imageRotation) % 4
+	draw.Draw(vd.panel, vd.model.btnRect(btnIndex), inverse.apply(img), image.Point{0, 0}, draw.Src)
+}
+
+// Image returns a snapshot of the panel as currently shown by the virtual device.
+func (vd *VirtualDevice) Image() *image.RGBA {
+	vd.Lock()
+	defer vd.Unlock()
+	img := image.NewRGBA(vd.panel.Bounds())
+	draw.Draw(img, img.Bounds(), vd.panel, image.Point{0, 0}, draw.Src)
+	return img
+}
+
+// WritePNG encodes the current panel as PNG into w.
+func (vd *VirtualDevice) WritePNG(w io.Writer) error {
+	return png.Encode(w, vd.Image())
+}
+
+// Press simulates pressing a button.
+func (vd *VirtualDevice) Press(btnIndex int) error {
+	return vd.setBtnState(btnIndex, 1)
+}
+
+// Release simulates releasing a button.
+func (vd *VirtualDevice) Release(btnIndex int) error {
+	return vd.setBtnState(btnIndex, 0)
+}
+
+// setBtnState updates the state of a button and queues the corresponding
+// input report.
+func (vd *VirtualDevice) setBtnState(btnIndex int, state byte) error {
+	vd.Lock()
+	defer vd.Unlock()
+
+	if btnIndex < 0 || btnIndex >= len(vd.btnState) {
+		return fmt.Errorf("invalid key index")
+	}
+	vd.btnState[btnIndex] = state
+
+	report := make([]byte, vd.model.InputReportSize)
+	report[0] = 0x01
+	copy(report[vd.model.inputReportOffset:], vd.btnState)
+
+	select {
+	case vd.reports <- report:
+		return nil
+	default:
+		return fmt.Errorf("input report queue full")
+	}
+}
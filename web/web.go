@@ -0,0 +1,142 @@
+// Package web exposes a Stream Deck through a WebSocket endpoint, so that it
+// can be monitored and controlled from a browser. Button events are streamed
+// to the clients as JSON messages; clients can send commands to fill a
+// button with an image, write text on a button or set the brightness.
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"net/http"
+	"sync"
+
+	sd "github.com/AKovalevich/streamdeck"
+	"github.com/AKovalevich/streamdeck/label"
+	"github.com/gorilla/websocket"
+)
+
+// Message is sent from the server to the clients.
+type Message struct {
+	// Type is either "event" for button events or "result" as response
+	// to a command.
+	Type string `json:"type"`
+	// Btn is the index of the button.
+	Btn int `json:"btn"`
+	// State is "pressed" or "released" for events.
+	State string `json:"state,omitempty"`
+	// Cmd is the command a result refers to.
+	Cmd string `json:"cmd,omitempty"`
+	// Error contains the error message if a command failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Command is sent from the clients to the server.
+type Command struct {
+	// Cmd is one of "image", "text" or "brightness".
+	Cmd string `json:"cmd"`
+	// Btn is the index of the button for the "image" and "text" commands.
+	Btn int `json:"btn"`
+	// Image is the base64 encoded image (png, jpeg or gif) for the
+	// "image" command.
+	Image string `json:"image,omitempty"`
+	// Text is the text (max 5 characters) for the "text" command.
+	Text string `json:"text,omitempty"`
+	// Brightness is the brightness in percent for the "brightness" command.
+	Brightness int `json:"brightness,omitempty"`
+}
+
+// Server is a http.Handler serving the WebSocket endpoint.
+type Server struct {
+	streamDeck *sd.StreamDeck
+	upgrader   websocket.Upgrader
+}
+
+// NewServer is the constructor of a Server for the given Stream Deck.
+func NewServer(streamDeck *sd.StreamDeck) (*Server, error) {
+	if streamDeck == nil {
+		return nil, fmt.Errorf("stream deck must not be nil")
+	}
+	return &Server{
+		streamDeck: streamDeck,
+	}, nil
+}
+
+// ServeHTTP upgrades the connection to a WebSocket and handles it until
+// the client disconnects.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.streamDeck.Log().Warn(err.Error())
+		return
+	}
+	defer conn.Close()
+
+	// gorilla/websocket supports only one concurrent writer
+	var writeMu sync.Mutex
+	send := func(msg Message) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(msg)
+	}
+
+	events, cancel := s.streamDeck.Subscribe()
+	defer cancel()
+
+	go func() {
+		for ev := range events {
+			msg := Message{Type: "event", Btn: ev.BtnIndex, State: stateName(ev.State)}
+			if err := send(msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var cmd Command
+		if err := conn.ReadJSON(&cmd); err != nil {
+			return
+		}
+		res := Message{Type: "result", Btn: cmd.Btn, Cmd: cmd.Cmd}
+		if err := s.execute(cmd); err != nil {
+			res.Error = err.Error()
+		}
+		if err := send(res); err != nil {
+			return
+		}
+	}
+}
+
+// execute runs a command on the Stream Deck.
+func (s *Server) execute(cmd Command) error {
+	switch cmd.Cmd {
+	case "image":
+		data, err := base64.StdEncoding.DecodeString(cmd.Image)
+		if err != nil {
+			return fmt.Errorf("invalid base64 image: %v", err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		return s.streamDeck.FillImage(cmd.Btn, img)
+	case "text":
+		l, err := label.NewLabel(s.streamDeck, cmd.Btn, label.Text(cmd.Text))
+		if err != nil {
+			return err
+		}
+		return l.Draw()
+	case "brightness":
+		return s.streamDeck.SetBrightness(cmd.Brightness)
+	default:
+		return fmt.Errorf("unknown command %q", cmd.Cmd)
+	}
+}
+
+func stateName(state sd.BtnState) string {
+	if state == sd.BtnPressed {
+		return "pressed"
+	}
+	return "released"
+}